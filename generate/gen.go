@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -524,6 +526,14 @@ func (f StructField) WriteDecode(l *LineWriter) {
 }
 
 func (s Struct) WriteDecode(l *LineWriter) {
+	s.writeDecode(l, false)
+}
+
+// writeDecode writes the decoding of all fields in s. If track is true, the
+// name of each field is appended to the read variable after the field is
+// successfully decoded; this is used for top level responses to support
+// partial decoding.
+func (s Struct) writeDecode(l *LineWriter, track bool) {
 	if len(s.Fields) == 0 {
 		return
 	}
@@ -547,6 +557,9 @@ func (s Struct) WriteDecode(l *LineWriter) {
 			continue
 		}
 		f.WriteDecode(l)
+		if track {
+			f.writeTrack(l)
+		}
 		l.Write("}")
 	}
 
@@ -583,9 +596,18 @@ func (s Struct) WriteDecode(l *LineWriter) {
 		l.Write("if err := b.Complete(); err != nil {")
 		l.Write("return err")
 		l.Write("}")
+		if track {
+			f.writeTrack(l)
+		}
 	}
 }
 
+func (f StructField) writeTrack(l *LineWriter) {
+	l.Write("if read != nil && b.Ok() {")
+	l.Write("*read = append(*read, %q)", f.FieldName)
+	l.Write("}")
+}
+
 func (s Struct) WriteDefault(l *LineWriter) {
 	if len(s.Fields) == 0 || s.Nullable {
 		return
@@ -754,15 +776,23 @@ func (s Struct) WriteAppendFunc(l *LineWriter) {
 }
 
 func (s Struct) WriteDecodeFunc(l *LineWriter) {
+	// Top level responses track which fields are read, which is used by
+	// ReadResponsePartial.
+	track := s.TopLevel && s.RequestKind != ""
+	trackArg, trackParam := "", ""
+	if track {
+		trackArg, trackParam = ", nil", ", read *[]string"
+	}
+
 	l.Write("func (v *%s) ReadFrom(src []byte) error {", s.Name)
-	l.Write("return v.readFrom(src, false)")
+	l.Write("return v.readFrom(src, false%s)", trackArg)
 	l.Write("}")
 
 	l.Write("func (v *%s) UnsafeReadFrom(src []byte) error {", s.Name)
-	l.Write("return v.readFrom(src, true)")
+	l.Write("return v.readFrom(src, true%s)", trackArg)
 	l.Write("}")
 
-	l.Write("func (v *%s) readFrom(src []byte, unsafe bool) error {", s.Name)
+	l.Write("func (v *%s) readFrom(src []byte, unsafe bool%s) error {", s.Name, trackParam)
 	l.Write("v.Default()")
 	l.Write("b := kbin.Reader{Src: src}")
 	if s.WithVersionField {
@@ -776,7 +806,7 @@ func (s Struct) WriteDecodeFunc(l *LineWriter) {
 		l.Write("isFlexible := version >= %d", s.FlexibleAt)
 		l.Write("_ = isFlexible")
 	}
-	s.WriteDecode(l)
+	s.writeDecode(l, track)
 	l.Write("return b.Complete()")
 	l.Write("}")
 }
@@ -792,6 +822,17 @@ func (s Struct) WriteRequestWithFunc(l *LineWriter) {
 	l.Write("}")
 }
 
+func (s Struct) WriteReadResponseFromFunc(l *LineWriter) {
+	l.Write("// ReadResponseFrom returns a new response at v's version decoded from body,")
+	l.Write("// which must not include the response header.")
+	l.Write("func (v *%s) ReadResponseFrom(body []byte) (*%s, error) {", s.Name, s.ResponseKind)
+	l.Write("resp := &%s{Version: v.Version}", s.ResponseKind)
+	l.Write("resp.Default()")
+	l.Write("err := resp.ReadFrom(body)")
+	l.Write("return resp, err")
+	l.Write("}")
+}
+
 func (s Struct) WriteDefaultFunc(l *LineWriter) {
 	l.Write("// Default sets any default fields. Calling this allows for future compatibility")
 	l.Write("// if new fields are added to %s.", s.Name)
@@ -949,3 +990,165 @@ func (e Enum) WriteConsts(l *LineWriter) {
 		l.Write("%s%s %s = %d", e.Name, sb.String(), e.Name, v.Value)
 	}
 }
+
+// collectSentinels appends the dotted path of every integer field in s that
+// has a negative default, as well as that default.
+func (s Struct) collectSentinels(prefix string, paths []string, sentinels map[string]int64) []string {
+	for _, f := range s.Fields {
+		path := prefix + f.FieldName
+		switch inner := f.Type.(type) {
+		case Struct:
+			paths = inner.collectSentinels(path+".", paths, sentinels)
+		case Array:
+			if s, ok := inner.Inner.(Struct); ok {
+				paths = s.collectSentinels(path+".", paths, sentinels)
+			}
+		case Defaulter:
+			def, has := inner.GetDefault()
+			if !has {
+				continue
+			}
+			i, err := strconv.ParseInt(fmt.Sprint(def), 10, 64)
+			if err != nil || i >= 0 {
+				continue
+			}
+			if prior, exists := sentinels[path]; exists {
+				if prior != i {
+					die("field %s has a different sentinel in the request (%d) than in the response (%d)", path, prior, i)
+				}
+				continue
+			}
+			sentinels[path] = i
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func writeFieldSentinels(l *LineWriter, reqs []Struct) {
+	resps := make(map[string]Struct)
+	for _, s := range newStructs {
+		if s.TopLevel && s.RequestKind != "" {
+			resps[s.Name] = s
+		}
+	}
+
+	l.Write("// FieldSentinel returns the sentinel value that signifies \"unset\" for an")
+	l.Write("// integer field in the request or response for the given key. The field is")
+	l.Write("// the dotted path to the field from the top level struct, for example")
+	l.Write("// \"Topics.Partitions.CurrentLeaderEpoch\" for the FetchRequest. Request fields")
+	l.Write("// are checked before response fields; if a path exists in both the request")
+	l.Write("// and response, the sentinel is the same.")
+	l.Write("//")
+	l.Write("// Sentinels are the negative defaults that Kafka uses for fields that are")
+	l.Write("// optional or not yet known (e.g., -1 for a leader epoch or producer ID).")
+	l.Write("// This returns false if the field does not exist or has no sentinel.")
+	l.Write("func FieldSentinel(key int16, field string) (int64, bool) {")
+	l.Write("switch key {")
+	for _, req := range reqs {
+		sentinels := make(map[string]int64)
+		paths := req.collectSentinels("", nil, sentinels)
+		paths = resps[strings.TrimSuffix(req.Name, "Request")+"Response"].collectSentinels("", paths, sentinels)
+		if len(paths) == 0 {
+			continue
+		}
+		l.Write("case %d:", req.Key)
+		l.Write("switch field {")
+		for _, path := range paths {
+			l.Write("case %q: return %d, true", path, sentinels[path])
+		}
+		l.Write("}")
+	}
+	l.Write("}")
+	l.Write("return 0, false")
+	l.Write("}")
+}
+
+var kipRe = regexp.MustCompile(`KIP-(\d+)`)
+
+// collectKIPs adds every KIP number referenced in the comments of s and all
+// of its nested fields to kips.
+func (s Struct) collectKIPs(kips map[int]bool) {
+	add := func(comment string) {
+		for _, m := range kipRe.FindAllStringSubmatch(comment, -1) {
+			n, _ := strconv.Atoi(m[1])
+			kips[n] = true
+		}
+	}
+	add(s.Comment)
+	for _, f := range s.Fields {
+		add(f.Comment)
+		switch inner := f.Type.(type) {
+		case Struct:
+			inner.collectKIPs(kips)
+		case Array:
+			if s, ok := inner.Inner.(Struct); ok {
+				s.collectKIPs(kips)
+			}
+		}
+	}
+}
+
+func writeKIPs(l *LineWriter, reqs []Struct) {
+	resps := make(map[string]Struct)
+	for _, s := range newStructs {
+		if s.TopLevel && s.RequestKind != "" {
+			resps[s.Name] = s
+		}
+	}
+
+	l.Write("// KIPFor returns the Kafka Improvement Proposals (e.g. \"KIP-320\") that are")
+	l.Write("// referenced in the documentation of the request and response for the given")
+	l.Write("// key, in numeric order. This returns nil if the key is unknown or if no KIPs")
+	l.Write("// are referenced.")
+	l.Write("func KIPFor(key int16) []string {")
+	l.Write("switch key {")
+	for _, req := range reqs {
+		kips := make(map[int]bool)
+		req.collectKIPs(kips)
+		resps[req.ResponseKind].collectKIPs(kips)
+		if len(kips) == 0 {
+			continue
+		}
+		nums := make([]int, 0, len(kips))
+		for n := range kips {
+			nums = append(nums, n)
+		}
+		sort.Ints(nums)
+		strs := make([]string, 0, len(nums))
+		for _, n := range nums {
+			strs = append(strs, fmt.Sprintf("%q", fmt.Sprintf("KIP-%d", n)))
+		}
+		l.Write("case %d:", req.Key)
+		l.Write("return []string{%s}", strings.Join(strs, ", "))
+	}
+	l.Write("}")
+	l.Write("return nil")
+	l.Write("}")
+}
+
+// writeModeledTags writes a function returning the tag keys of the tagged
+// fields of every struct that has any, which ValidateTags uses to detect
+// unknown tags that duplicate a modeled tag.
+func writeModeledTags(l *LineWriter) {
+	l.Write("// modeledTags returns the tag keys of the tagged fields of v, which is a")
+	l.Write("// pointer to a struct in this package, or nil if v has no tagged fields.")
+	l.Write("func modeledTags(v interface{}) []uint32 {")
+	l.Write("switch v.(type) {")
+	for _, s := range newStructs {
+		var tags []string
+		for _, f := range s.Fields {
+			if f.Tag >= 0 {
+				tags = append(tags, strconv.Itoa(f.Tag))
+			}
+		}
+		if len(tags) == 0 {
+			continue
+		}
+		l.Write("case *%s:", s.Name)
+		l.Write("return []uint32{%s}", strings.Join(tags, ", "))
+	}
+	l.Write("}")
+	l.Write("return nil")
+	l.Write("}")
+}
@@ -475,6 +475,7 @@ func main() {
 				}
 				s.WriteResponseKindFunc(l)
 				s.WriteRequestWithFunc(l)
+				s.WriteReadResponseFromFunc(l)
 			}
 			if s.RequestKind != "" {
 				s.WriteRequestKindFunc(l)
@@ -546,6 +547,10 @@ func main() {
 	l.Write("// Int16 is an alias for int16(k).")
 	l.Write("func (k Key) Int16() int16 { return int16(k) }")
 
+	writeFieldSentinels(l, name2structs)
+	writeKIPs(l, name2structs)
+	writeModeledTags(l)
+
 	for _, e := range newEnums {
 		e.WriteDefn(l)
 		e.WriteStringFunc(l)
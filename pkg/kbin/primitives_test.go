@@ -3,6 +3,7 @@ package kbin
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"testing"
 	"testing/quick"
@@ -105,3 +106,61 @@ func BenchmarkUvarint(b *testing.B) {
 		})
 	}
 }
+
+func TestVarintChecked(t *testing.T) {
+	for i, test := range []struct {
+		in      []byte
+		long    bool
+		exp     int64
+		expErr  error
+		expLeft int
+	}{
+		{in: AppendVarint(nil, -300), exp: -300},
+		{in: append(AppendVarint(nil, 5), 1), exp: 5, expLeft: 1},
+		{in: AppendVarlong(nil, -1<<40), long: true, exp: -1 << 40},
+
+		{in: nil, expErr: ErrNotEnoughData},
+		{in: []byte{0x80, 0x80}, expErr: ErrNotEnoughData},
+		{in: []byte{0x80, 0x80}, long: true, expErr: ErrNotEnoughData},
+
+		// Five bytes with the fifth byte setting bits beyond 32.
+		{in: []byte{0xff, 0xff, 0xff, 0xff, 0x1f}, expErr: ErrVarintOverflow},
+		// Too many continuation bytes.
+		{in: []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x01}, expErr: ErrVarintOverflow},
+		{in: append(bytes.Repeat([]byte{0x80}, 10), 0x01), long: true, expErr: ErrVarintOverflow},
+	} {
+		b := Reader{Src: test.in}
+		var got int64
+		var err error
+		if test.long {
+			got, err = b.VarlongChecked()
+		} else {
+			var got32 int32
+			got32, err = b.VarintChecked()
+			got = int64(got32)
+		}
+		if err != test.expErr {
+			t.Errorf("#%d: got err %v != exp %v", i, err, test.expErr)
+		}
+		if got != test.exp {
+			t.Errorf("#%d: got %d != exp %d", i, got, test.exp)
+		}
+		if len(b.Src) != test.expLeft {
+			t.Errorf("#%d: got %d bytes left != exp %d", i, len(b.Src), test.expLeft)
+		}
+		if (err == nil) != b.Ok() {
+			t.Errorf("#%d: reader ok %v does not match err %v", i, b.Ok(), err)
+		}
+	}
+}
+
+func TestErrShort(t *testing.T) {
+	var err error = &ErrShort{Need: 10, Have: 4}
+	if !errors.Is(err, ErrNotEnoughData) {
+		t.Error("ErrShort is not ErrNotEnoughData")
+	}
+	var short *ErrShort
+	if !errors.As(err, &short) || short.Need != 10 || short.Have != 4 {
+		t.Errorf("got %v, expected need 10 have 4", short)
+	}
+}
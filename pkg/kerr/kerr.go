@@ -31,6 +31,109 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("%s: %s", e.Message, e.Description)
 }
 
+// Name returns the name of the error, e.g. NOT_COORDINATOR. This is the same
+// as Message, and is provided for code that wants a stable label for the
+// error, such as for metrics.
+func (e *Error) Name() string {
+	return e.Message
+}
+
+// IsUnsupportedVersion returns whether the error is UNSUPPORTED_VERSION, which
+// a broker returns if it does not support the version of a request. The
+// usual handling is to retry the request at a lower version.
+func (e *Error) IsUnsupportedVersion() bool {
+	return e != nil && e.Code == UnsupportedVersion.Code
+}
+
+// IsUnstableOffset returns whether the error is UNSTABLE_OFFSET_COMMIT, which
+// a broker returns from an OffsetFetch with RequireStable if the group has
+// offsets pending in an open transaction. The error is retriable: the usual
+// handling is to back off and retry the fetch after the transaction commits
+// or aborts.
+func (e *Error) IsUnstableOffset() bool {
+	return e != nil && e.Code == UnstableOffsetCommit.Code
+}
+
+// IsFencedInstance returns whether the error is FENCED_INSTANCE_ID, which a
+// broker returns to a static group member (KIP-345) if another member has
+// joined the group with the same group.instance.id. The error is not
+// retriable: the member has been replaced and must shut down rather than
+// rejoin the group, which would only fence the new member in turn.
+func (e *Error) IsFencedInstance() bool {
+	return e != nil && e.Code == FencedInstanceID.Code
+}
+
+// IsInvalidRecord returns whether the error is INVALID_RECORD, which a broker
+// returns from a produce if a record failed validation, such as a record with
+// a null key produced to a compacted topic or a timestamp outside of the
+// topic's allowed range. The error is not retriable: the batch will fail the
+// same way every time.
+//
+// For v8+ produce responses, the partition's ErrorRecords pinpoint the
+// relative offset of each record that failed and why, and the partition's
+// ErrorMessage summarizes the failure. Converting the partition's ErrorCode and
+// ErrorMessage with ErrorForCodeWithMessage preserves the explanation.
+func (e *Error) IsInvalidRecord() bool {
+	return e != nil && e.Code == InvalidRecord.Code
+}
+
+// Key returns the error code of the error. Wrapped copies of an error, such
+// as an *ErrorWithMessage, are different values than the package level
+// errors, but they unwrap to the package level error for their code. The
+// package level errors themselves are safe to use as map keys by identity;
+// Key allows keying maps by code for errors that may be wrapped.
+func (e *Error) Key() int16 {
+	return e.Code
+}
+
+// Attributes returns the error as structured attributes suitable for
+// annotating a tracing span or structured log: "kafka.error.code" (an int16),
+// "kafka.error.name" (a string), and "kafka.error.retriable" (a bool).
+func (e *Error) Attributes() map[string]interface{} {
+	return map[string]interface{}{
+		"kafka.error.code":      e.Code,
+		"kafka.error.name":      e.Name(),
+		"kafka.error.retriable": e.Retriable,
+	}
+}
+
+// ErrorAttributes returns the Attributes of the Kafka error in err, unwrapping
+// err as necessary, or nil if err does not wrap a Kafka error.
+func ErrorAttributes(err error) map[string]interface{} {
+	var kerr *Error
+	if !errors.As(err, &kerr) {
+		return nil
+	}
+	return kerr.Attributes()
+}
+
+// CountByCode returns the number of errors in errs for each Kafka error code.
+// Errors are unwrapped to find the underlying *Error; errors that are not
+// Kafka errors, and nil errors, are not counted.
+func CountByCode(errs []error) map[int16]int {
+	counts := make(map[int16]int)
+	for _, err := range errs {
+		var kerr *Error
+		if errors.As(err, &kerr) {
+			counts[kerr.Key()]++
+		}
+	}
+	return counts
+}
+
+// NameForCode returns the name of the error for the given error code, e.g.
+// NOT_COORDINATOR. This returns NONE for 0 and UNKNOWN for unknown codes.
+func NameForCode(code int16) string {
+	err, exists := code2err[code]
+	if !exists {
+		return "UNKNOWN"
+	}
+	if err == nil {
+		return "NONE"
+	}
+	return err.(*Error).Message
+}
+
 // ErrorForCode returns the error corresponding to the given error code.
 //
 // If the code is unknown, this returns UnknownServerError.
@@ -66,12 +169,112 @@ func TypedErrorForCode(code int16) *Error {
 	return err.(*Error)
 }
 
+// ErrorWithMessage is a Kafka error along with the human readable error
+// message that a broker returned alongside the error code. The message
+// usually explains why the error occurred.
+type ErrorWithMessage struct {
+	// Err is the Kafka error corresponding to the error code.
+	Err *Error
+	// Message is the error message the broker returned.
+	Message string
+}
+
+func (e *ErrorWithMessage) Error() string {
+	return fmt.Sprintf("%s: %s", e.Err.Error(), e.Message)
+}
+
+// Unwrap returns the underlying Kafka error, allowing errors.Is and errors.As
+// to see through the message.
+func (e *ErrorWithMessage) Unwrap() error {
+	return e.Err
+}
+
+// ErrorForCodeWithMessage is the same as ErrorForCode, but if msg is
+// non-empty and the code is non-zero, the returned error is an
+// *ErrorWithMessage that includes msg. The underlying *Error can still be
+// checked with errors.Is and errors.As.
+func ErrorForCodeWithMessage(code int16, msg string) error {
+	err := ErrorForCode(code)
+	if err == nil || msg == "" {
+		return err
+	}
+	return &ErrorWithMessage{Err: err.(*Error), Message: msg}
+}
+
 // IsRetriable returns whether a Kafka error is considered retriable.
 func IsRetriable(err error) bool {
 	var kerr *Error
 	return errors.As(err, &kerr) && kerr.Retriable
 }
 
+// IsProduceDuplicate returns whether err is DUPLICATE_SEQUENCE_NUMBER. For an
+// idempotent producer, this means the batch being produced was already
+// written, and the produce can be treated as successful.
+func IsProduceDuplicate(err error) bool {
+	return errors.Is(err, DuplicateSequenceNumber)
+}
+
+// IsFatalProduceError returns whether err is fatal to an idempotent producer's
+// current producer ID and epoch (KIP-98, KIP-360). These errors mean the
+// producer's sequence numbers can no longer be trusted; the producer must
+// stop producing and reset its producer ID (or epoch) before continuing.
+//
+// The fatal errors are OUT_OF_ORDER_SEQUENCE_NUMBER, INVALID_PRODUCER_EPOCH,
+// UNKNOWN_PRODUCER_ID, and INVALID_PRODUCER_ID_MAPPING.
+func IsFatalProduceError(err error) bool {
+	var kerr *Error
+	if !errors.As(err, &kerr) {
+		return false
+	}
+	switch kerr.Code {
+	case OutOfOrderSequenceNumber.Code,
+		InvalidProducerEpoch.Code,
+		UnknownProducerID.Code,
+		InvalidProducerIDMapping.Code:
+		return true
+	}
+	return false
+}
+
+// RebalanceAction is the recovery a group member must take after a group
+// error.
+type RebalanceAction int8
+
+const (
+	// RebalanceNone means the error does not require rejoining the group.
+	RebalanceNone RebalanceAction = iota
+	// RebalanceRejoin means the member must rejoin the group with its
+	// current member ID. This is the action for REBALANCE_IN_PROGRESS and
+	// ILLEGAL_GENERATION.
+	RebalanceRejoin
+	// RebalanceResetMemberID means the member must clear its member ID
+	// and rejoin the group as a new member. This is the action for
+	// UNKNOWN_MEMBER_ID.
+	RebalanceResetMemberID
+	// RebalanceUseProvidedMemberID means the member must rejoin the group
+	// using the member ID the broker returned in the JoinGroupResponse
+	// (KIP-394). This is the action for MEMBER_ID_REQUIRED.
+	RebalanceUseProvidedMemberID
+)
+
+// RebalanceActionFor returns the action a group member must take to recover
+// from err, or RebalanceNone if err is not a group rebalance error.
+func RebalanceActionFor(err error) RebalanceAction {
+	var kerr *Error
+	if !errors.As(err, &kerr) {
+		return RebalanceNone
+	}
+	switch kerr.Code {
+	case RebalanceInProgress.Code, IllegalGeneration.Code:
+		return RebalanceRejoin
+	case UnknownMemberID.Code:
+		return RebalanceResetMemberID
+	case MemberIDRequired.Code:
+		return RebalanceUseProvidedMemberID
+	}
+	return RebalanceNone
+}
+
 var (
 	UnknownServerError                 = &Error{"UNKNOWN_SERVER_ERROR", -1, false, "The server experienced an unexpected error when processing the request."}
 	OffsetOutOfRange                   = &Error{"OFFSET_OUT_OF_RANGE", 1, false, "The requested offset is not within the range of offsets maintained by the server."}
@@ -0,0 +1,183 @@
+package kerr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProduceErrors(t *testing.T) {
+	for _, test := range []struct {
+		err       error
+		duplicate bool
+		fatal     bool
+	}{
+		{nil, false, false},
+		{fmt.Errorf("not kafka"), false, false},
+		{DuplicateSequenceNumber, true, false},
+		{fmt.Errorf("wrapped: %w", DuplicateSequenceNumber), true, false},
+		{OutOfOrderSequenceNumber, false, true},
+		{InvalidProducerEpoch, false, true},
+		{UnknownProducerID, false, true},
+		{fmt.Errorf("wrapped: %w", InvalidProducerIDMapping), false, true},
+		{NotLeaderForPartition, false, false},
+		{ProducerFenced, false, false},
+	} {
+		if got := IsProduceDuplicate(test.err); got != test.duplicate {
+			t.Errorf("%v: got duplicate %v != exp %v", test.err, got, test.duplicate)
+		}
+		if got := IsFatalProduceError(test.err); got != test.fatal {
+			t.Errorf("%v: got fatal %v != exp %v", test.err, got, test.fatal)
+		}
+	}
+}
+
+func TestNameForCode(t *testing.T) {
+	for _, test := range []struct {
+		code int16
+		exp  string
+	}{
+		{0, "NONE"},
+		{-1, "UNKNOWN_SERVER_ERROR"},
+		{16, "NOT_COORDINATOR"},
+		{-2, "UNKNOWN"},
+		{30000, "UNKNOWN"},
+	} {
+		if got := NameForCode(test.code); got != test.exp {
+			t.Errorf("code %d: got %s != exp %s", test.code, got, test.exp)
+		}
+	}
+	if got := NotCoordinator.Name(); got != "NOT_COORDINATOR" {
+		t.Errorf("got name %s != exp NOT_COORDINATOR", got)
+	}
+}
+
+func TestIsUnsupportedVersion(t *testing.T) {
+	if !UnsupportedVersion.IsUnsupportedVersion() {
+		t.Error("UNSUPPORTED_VERSION is not unsupported version")
+	}
+	if NotCoordinator.IsUnsupportedVersion() {
+		t.Error("NOT_COORDINATOR is unexpectedly unsupported version")
+	}
+	if TypedErrorForCode(0).IsUnsupportedVersion() {
+		t.Error("nil error is unexpectedly unsupported version")
+	}
+}
+
+func TestIsUnstableOffset(t *testing.T) {
+	err := TypedErrorForCode(88)
+	if err != UnstableOffsetCommit || !err.IsUnstableOffset() || !IsRetriable(err) {
+		t.Errorf("got %v for code 88, expected a retriable UNSTABLE_OFFSET_COMMIT", err)
+	}
+	if NotCoordinator.IsUnstableOffset() {
+		t.Error("NOT_COORDINATOR is unexpectedly unstable offset")
+	}
+	if TypedErrorForCode(0).IsUnstableOffset() {
+		t.Error("nil error is unexpectedly unstable offset")
+	}
+}
+
+func TestIsFencedInstance(t *testing.T) {
+	err := TypedErrorForCode(82)
+	if err != FencedInstanceID || !err.IsFencedInstance() || IsRetriable(err) {
+		t.Errorf("got %v for code 82, expected a non-retriable FENCED_INSTANCE_ID", err)
+	}
+	if UnknownMemberID.IsFencedInstance() {
+		t.Error("UNKNOWN_MEMBER_ID is unexpectedly fenced instance")
+	}
+	if TypedErrorForCode(0).IsFencedInstance() {
+		t.Error("nil error is unexpectedly fenced instance")
+	}
+}
+
+func TestIsInvalidRecord(t *testing.T) {
+	err := TypedErrorForCode(87)
+	if err != InvalidRecord || !err.IsInvalidRecord() || IsRetriable(err) {
+		t.Errorf("got %v for code 87, expected a non-retriable INVALID_RECORD", err)
+	}
+	if CorruptMessage.IsInvalidRecord() {
+		t.Error("CORRUPT_MESSAGE is unexpectedly invalid record")
+	}
+
+	withMsg := ErrorForCodeWithMessage(87, "Compacted topic cannot accept message without key")
+	var kerr *Error
+	if !errors.As(withMsg, &kerr) || !kerr.IsInvalidRecord() {
+		t.Errorf("got %v, expected an INVALID_RECORD", withMsg)
+	}
+	if !strings.Contains(withMsg.Error(), "without key") {
+		t.Errorf("got %q, expected the message to be surfaced", withMsg)
+	}
+}
+
+func TestErrorForCodeWithMessage(t *testing.T) {
+	if err := ErrorForCodeWithMessage(0, "msg"); err != nil {
+		t.Errorf("got %v for code 0, expected nil", err)
+	}
+	if err := ErrorForCodeWithMessage(16, ""); err != NotCoordinator {
+		t.Errorf("got %v for no message, expected NOT_COORDINATOR", err)
+	}
+	err := ErrorForCodeWithMessage(16, "coordinator moved")
+	if !errors.Is(err, NotCoordinator) || !IsRetriable(err) {
+		t.Errorf("got %v, expected a retriable NOT_COORDINATOR", err)
+	}
+	if exp := NotCoordinator.Error() + ": coordinator moved"; err.Error() != exp {
+		t.Errorf("got %q != exp %q", err.Error(), exp)
+	}
+}
+
+func TestCountByCode(t *testing.T) {
+	errs := []error{
+		NotCoordinator,
+		fmt.Errorf("wrapped: %w", NotCoordinator),
+		ErrorForCodeWithMessage(16, "coordinator moved"),
+		ErrorForCodeWithMessage(3, "unknown topic"),
+		ErrorForCode(3),
+		errors.New("not kafka"),
+		nil,
+	}
+	exp := map[int16]int{16: 3, 3: 2}
+	got := CountByCode(errs)
+	if len(got) != len(exp) || got[16] != exp[16] || got[3] != exp[3] {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+	if NotCoordinator.Key() != 16 {
+		t.Errorf("got key %d != exp 16", NotCoordinator.Key())
+	}
+}
+
+func TestRebalanceActionFor(t *testing.T) {
+	for _, test := range []struct {
+		err error
+		exp RebalanceAction
+	}{
+		{RebalanceInProgress, RebalanceRejoin},
+		{IllegalGeneration, RebalanceRejoin},
+		{UnknownMemberID, RebalanceResetMemberID},
+		{ErrorForCodeWithMessage(79, "use this member ID"), RebalanceUseProvidedMemberID},
+		{fmt.Errorf("wrapped: %w", RebalanceInProgress), RebalanceRejoin},
+		{NotCoordinator, RebalanceNone},
+		{errors.New("not kafka"), RebalanceNone},
+		{nil, RebalanceNone},
+	} {
+		if got := RebalanceActionFor(test.err); got != test.exp {
+			t.Errorf("%v: got %d != exp %d", test.err, got, test.exp)
+		}
+	}
+}
+
+func TestErrorAttributes(t *testing.T) {
+	err := fmt.Errorf("commit failed: %w", ErrorForCodeWithMessage(27, "rebalancing"))
+	exp := map[string]interface{}{
+		"kafka.error.code":      int16(27),
+		"kafka.error.name":      "REBALANCE_IN_PROGRESS",
+		"kafka.error.retriable": false,
+	}
+	if got := ErrorAttributes(err); !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+	if got := ErrorAttributes(errors.New("not kafka")); got != nil {
+		t.Errorf("got %v for a non-Kafka error, expected nil", got)
+	}
+}
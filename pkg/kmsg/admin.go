@@ -0,0 +1,275 @@
+package kmsg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Results returns a map of topics to partitions to the result of the leader
+// election for that partition: nil if a new leader was elected, or otherwise a
+// PartitionFailure, the code of which can be converted to a kerr error with
+// kerr.ErrorForCode.
+//
+// ELECTION_NOT_NEEDED (84) means the partition's preferred leader is already
+// the leader, and is usually not a failure. PREFERRED_LEADER_NOT_AVAILABLE
+// (80) and ELIGIBLE_LEADERS_NOT_AVAILABLE (83) mean no leader could be
+// elected.
+//
+// For v1+, a non-zero top level ErrorCode applies to the request as a whole,
+// and the response may not contain any partitions.
+func (v *ElectLeadersResponse) Results() map[string]map[int32]error {
+	results := make(map[string]map[int32]error, len(v.Topics))
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		ps := results[t.Topic]
+		if ps == nil {
+			ps = make(map[int32]error, len(t.Partitions))
+			results[t.Topic] = ps
+		}
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			var err error
+			if p.ErrorCode != 0 {
+				f := PartitionFailure{Topic: t.Topic, Partition: p.Partition, ErrorCode: p.ErrorCode}
+				if p.ErrorMessage != nil {
+					f.ErrorMessage = *p.ErrorMessage
+				}
+				err = f
+			}
+			ps[p.Partition] = err
+		}
+	}
+	return results
+}
+
+// BuildAlterReassignments returns an AlterPartitionAssignmentsRequest
+// (AlterPartitionReassignments in Kafka, KIP-455) reassigning each topic
+// partition in m to the replicas in m. A nil replica slice cancels any
+// in-progress reassignment for the partition, which is encoded as null
+// replicas on the wire; an empty non-nil slice is not a cancellation and is
+// sent as is. Topics and partitions are sorted so that the request is
+// deterministic.
+func BuildAlterReassignments(m map[string]map[int32][]int32) *AlterPartitionAssignmentsRequest {
+	req := NewPtrAlterPartitionAssignmentsRequest()
+	for topic, partitions := range m {
+		rt := NewAlterPartitionAssignmentsRequestTopic()
+		rt.Topic = topic
+		for partition, replicas := range partitions {
+			rp := NewAlterPartitionAssignmentsRequestTopicPartition()
+			rp.Partition = partition
+			if replicas != nil {
+				rp.Replicas = append([]int32{}, replicas...)
+			}
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		sort.Slice(rt.Partitions, func(i, j int) bool { return rt.Partitions[i].Partition < rt.Partitions[j].Partition })
+		req.Topics = append(req.Topics, rt)
+	}
+	sort.Slice(req.Topics, func(i, j int) bool { return req.Topics[i].Topic < req.Topics[j].Topic })
+	return req
+}
+
+// Reassignments returns a map of topics to partitions to the in-progress
+// reassignment for that partition. Each reassignment contains the partition's
+// current replicas as well as the replicas being added and removed.
+func (v *ListPartitionReassignmentsResponse) Reassignments() map[string]map[int32]ListPartitionReassignmentsResponseTopicPartition {
+	reassignments := make(map[string]map[int32]ListPartitionReassignmentsResponseTopicPartition, len(v.Topics))
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		ps := reassignments[t.Topic]
+		if ps == nil {
+			ps = make(map[int32]ListPartitionReassignmentsResponseTopicPartition, len(t.Partitions))
+			reassignments[t.Topic] = ps
+		}
+		for _, p := range t.Partitions {
+			ps[p.Partition] = p
+		}
+	}
+	return reassignments
+}
+
+// Chunk splits the request into requests containing at most maxPerRequest
+// topics each, preserving the order of topics. Every returned request is a
+// copy of v with the same version, timeout, and ValidateOnly. If
+// maxPerRequest is not positive or v has at most maxPerRequest topics, this
+// returns a single copy of v.
+//
+// Creating many topics in one request risks hitting the broker side timeout;
+// chunking and issuing requests sequentially avoids this.
+func (v *CreateTopicsRequest) Chunk(maxPerRequest int) []*CreateTopicsRequest {
+	var chunks []*CreateTopicsRequest
+	for _, r := range chunkRange(len(v.Topics), maxPerRequest) {
+		c := *v
+		c.Topics = v.Topics[r[0]:r[1]:r[1]]
+		chunks = append(chunks, &c)
+	}
+	return chunks
+}
+
+// Chunk splits the request into requests containing at most maxPerRequest
+// topics each, preserving the order of topics. Both TopicNames (v0-v5) and
+// Topics (v6+) are split, meaning a request with both fields populated
+// identically can still be issued at any version. Every returned request is a
+// copy of v with the same version and timeout. If maxPerRequest is not
+// positive or v has at most maxPerRequest topics, this returns a single copy
+// of v.
+func (v *DeleteTopicsRequest) Chunk(maxPerRequest int) []*DeleteTopicsRequest {
+	n := len(v.Topics)
+	if len(v.TopicNames) > n {
+		n = len(v.TopicNames)
+	}
+	var chunks []*DeleteTopicsRequest
+	for _, r := range chunkRange(n, maxPerRequest) {
+		c := *v
+		c.TopicNames = nil
+		c.Topics = nil
+		if r[0] < len(v.TopicNames) {
+			end := r[1]
+			if end > len(v.TopicNames) {
+				end = len(v.TopicNames)
+			}
+			c.TopicNames = v.TopicNames[r[0]:end:end]
+		}
+		if r[0] < len(v.Topics) {
+			end := r[1]
+			if end > len(v.Topics) {
+				end = len(v.Topics)
+			}
+			c.Topics = v.Topics[r[0]:end:end]
+		}
+		chunks = append(chunks, &c)
+	}
+	return chunks
+}
+
+// chunkRange returns the [start, end) ranges to split n elements into chunks
+// of at most max elements each. This always returns at least one range.
+func chunkRange(n, max int) [][2]int {
+	if max <= 0 || n <= max {
+		return [][2]int{{0, n}}
+	}
+	var ranges [][2]int
+	for start := 0; start < n; start += max {
+		end := start + max
+		if end > n {
+			end = n
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges
+}
+
+// MaxTopicNameLength is the maximum length of a topic name.
+const MaxTopicNameLength = 249
+
+// ValidTopicName returns an error describing why name is not a valid topic
+// name, or nil if the name is valid. This checks the same rules the broker
+// uses to reject a topic with INVALID_TOPIC_EXCEPTION: a name must not be
+// empty, "." or "..", must be at most 249 characters, and must contain only
+// ASCII alphanumerics, '.', '_', and '-'.
+//
+// The broker allows names that contain both '.' and '_', but such a name
+// collides in metric names with any topic that differs only by swapping '.'
+// and '_'. Use TopicNamesCollide to check for this before creating a topic.
+func ValidTopicName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("invalid topic name: empty")
+	case name == "." || name == "..":
+		return fmt.Errorf("invalid topic name %q: cannot be \".\" or \"..\"", name)
+	case len(name) > MaxTopicNameLength:
+		return fmt.Errorf("invalid topic name %q: length %d is longer than the max of %d", name, len(name), MaxTopicNameLength)
+	}
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case c >= 'a' && c <= 'z',
+			c >= 'A' && c <= 'Z',
+			c >= '0' && c <= '9',
+			c == '.', c == '_', c == '-':
+		default:
+			return fmt.Errorf("invalid topic name %q: invalid character %q at index %d, only ASCII alphanumerics, '.', '_', and '-' are allowed", name, c, i)
+		}
+	}
+	return nil
+}
+
+// TopicNamesCollide returns whether two different topic names collide in
+// metric names, which happens if the names are equal after replacing every
+// '.' with '_'. The broker rejects creating a topic that collides with an
+// existing topic.
+func TopicNamesCollide(a, b string) bool {
+	return a != b && strings.ReplaceAll(a, ".", "_") == strings.ReplaceAll(b, ".", "_")
+}
+
+// NewPartitionCount is the target partition count for a topic in
+// BuildCreatePartitions.
+type NewPartitionCount struct {
+	// Current is the topic's current partition count, which is used to
+	// validate NewTotal and Assignments.
+	Current int32
+	// NewTotal is the total number of partitions the topic should have,
+	// which must be more than Current.
+	NewTotal int32
+	// Assignments, if non-nil, are the replicas for each new partition, in
+	// partition order. If non-nil, there must be exactly NewTotal-Current
+	// assignments, and each assignment must have at least one replica. If
+	// nil, the broker assigns replicas.
+	Assignments [][]int32
+}
+
+// validate returns an error if the count would be rejected by the broker with
+// INVALID_PARTITIONS or INVALID_REPLICA_ASSIGNMENT.
+func (c NewPartitionCount) validate() error {
+	if c.NewTotal <= c.Current {
+		return fmt.Errorf("new total partition count %d is not more than the current count %d", c.NewTotal, c.Current)
+	}
+	if c.Assignments == nil {
+		return nil
+	}
+	if added := c.NewTotal - c.Current; int32(len(c.Assignments)) != added {
+		return fmt.Errorf("got %d assignments for %d new partitions", len(c.Assignments), added)
+	}
+	for i, replicas := range c.Assignments {
+		if len(replicas) == 0 {
+			return fmt.Errorf("assignment %d has no replicas", i)
+		}
+	}
+	return nil
+}
+
+// BuildCreatePartitions returns a CreatePartitionsRequest to increase the
+// partition count of each topic in m, after validating each count locally:
+// the new total must be more than the current count, and any assignments must
+// have exactly one non-empty replica list per new partition. This returns an
+// error listing every invalid topic and a nil request if any topic is
+// invalid. Topics are sorted in the request so that the request is
+// deterministic.
+func BuildCreatePartitions(m map[string]NewPartitionCount) (*CreatePartitionsRequest, error) {
+	req := NewPtrCreatePartitionsRequest()
+	var invalid []string
+	for topic, c := range m {
+		if err := c.validate(); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", topic, err))
+			continue
+		}
+		rt := NewCreatePartitionsRequestTopic()
+		rt.Topic = topic
+		rt.Count = c.NewTotal
+		if c.Assignments != nil {
+			rt.Assignment = make([]CreatePartitionsRequestTopicAssignment, 0, len(c.Assignments))
+			for _, replicas := range c.Assignments {
+				ra := NewCreatePartitionsRequestTopicAssignment()
+				ra.Replicas = append([]int32{}, replicas...)
+				rt.Assignment = append(rt.Assignment, ra)
+			}
+		}
+		req.Topics = append(req.Topics, rt)
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, fmt.Errorf("invalid partition counts: %s", strings.Join(invalid, "; "))
+	}
+	sort.Slice(req.Topics, func(i, j int) bool { return req.Topics[i].Topic < req.Topics[j].Topic })
+	return req, nil
+}
@@ -0,0 +1,219 @@
+package kmsg
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestElectLeadersResults(t *testing.T) {
+	resp := NewPtrElectLeadersResponse()
+	for _, tp := range []struct {
+		topic     string
+		partition int32
+		code      int16
+	}{
+		{"foo", 0, 0},
+		{"foo", 1, 84}, // ELECTION_NOT_NEEDED
+		{"bar", 0, 80}, // PREFERRED_LEADER_NOT_AVAILABLE
+	} {
+		if len(resp.Topics) == 0 || resp.Topics[len(resp.Topics)-1].Topic != tp.topic {
+			rt := NewElectLeadersResponseTopic()
+			rt.Topic = tp.topic
+			resp.Topics = append(resp.Topics, rt)
+		}
+		rt := &resp.Topics[len(resp.Topics)-1]
+		rp := NewElectLeadersResponseTopicPartition()
+		rp.Partition = tp.partition
+		rp.ErrorCode = tp.code
+		rt.Partitions = append(rt.Partitions, rp)
+	}
+
+	exp := map[string]map[int32]error{
+		"foo": {
+			0: nil,
+			1: PartitionFailure{Topic: "foo", Partition: 1, ErrorCode: 84},
+		},
+		"bar": {0: PartitionFailure{Topic: "bar", Partition: 0, ErrorCode: 80}},
+	}
+	got := resp.Results()
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+
+	var f PartitionFailure
+	if !errors.As(got["foo"][1], &f) || f.ErrorCode != 84 {
+		t.Errorf("got %v, expected ELECTION_NOT_NEEDED (84) recognizable with errors.As", got["foo"][1])
+	}
+}
+
+func TestAlterReassignments(t *testing.T) {
+	req := BuildAlterReassignments(map[string]map[int32][]int32{
+		"foo": {
+			1: nil, // cancel
+			0: {1, 2, 3},
+		},
+	})
+	req.Version = 0
+	var got AlterPartitionAssignmentsRequest
+	if err := got.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read request: %v", err)
+	}
+	if len(got.Topics) != 1 || len(got.Topics[0].Partitions) != 2 {
+		t.Fatalf("got unexpected topics %v", got.Topics)
+	}
+	assign, cancel := got.Topics[0].Partitions[0], got.Topics[0].Partitions[1]
+	if assign.Partition != 0 || !reflect.DeepEqual(assign.Replicas, []int32{1, 2, 3}) {
+		t.Errorf("got assignment %v, expected partition 0 to replicas 1 2 3", assign)
+	}
+	if cancel.Partition != 1 || cancel.Replicas != nil {
+		t.Errorf("got cancellation %v, expected partition 1 with null replicas", cancel)
+	}
+
+	resp := NewPtrListPartitionReassignmentsResponse()
+	rt := NewListPartitionReassignmentsResponseTopic()
+	rt.Topic = "foo"
+	rp := NewListPartitionReassignmentsResponseTopicPartition()
+	rp.Partition = 0
+	rp.Replicas = []int32{1, 2, 3, 4}
+	rp.AddingReplicas = []int32{4}
+	rp.RemovingReplicas = []int32{1}
+	rt.Partitions = append(rt.Partitions, rp)
+	resp.Topics = append(resp.Topics, rt)
+
+	exp := map[string]map[int32]ListPartitionReassignmentsResponseTopicPartition{"foo": {0: rp}}
+	if got := resp.Reassignments(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+}
+
+func TestChunkTopics(t *testing.T) {
+	create := NewPtrCreateTopicsRequest()
+	create.Version = 4
+	create.TimeoutMillis = 1234
+	create.ValidateOnly = true
+	del := NewPtrDeleteTopicsRequest()
+	del.TimeoutMillis = 1234
+	for i := 0; i < 7; i++ {
+		name := string(rune('a' + i))
+		ct := NewCreateTopicsRequestTopic()
+		ct.Topic = name
+		create.Topics = append(create.Topics, ct)
+		del.TopicNames = append(del.TopicNames, name)
+		dt := NewDeleteTopicsRequestTopic()
+		dt.Topic = StringPtr(name)
+		del.Topics = append(del.Topics, dt)
+	}
+
+	creates := create.Chunk(3)
+	if len(creates) != 3 {
+		t.Fatalf("got %d create chunks != exp 3", len(creates))
+	}
+	var createTopics []string
+	for _, c := range creates {
+		if c.Version != 4 || c.TimeoutMillis != 1234 || !c.ValidateOnly || len(c.Topics) > 3 {
+			t.Errorf("got unexpected create chunk %v", c)
+		}
+		for _, t := range c.Topics {
+			createTopics = append(createTopics, t.Topic)
+		}
+	}
+
+	dels := del.Chunk(3)
+	if len(dels) != 3 {
+		t.Fatalf("got %d delete chunks != exp 3", len(dels))
+	}
+	var delNames, delTopics []string
+	for _, d := range dels {
+		if d.TimeoutMillis != 1234 || len(d.TopicNames) > 3 || len(d.Topics) != len(d.TopicNames) {
+			t.Errorf("got unexpected delete chunk %v", d)
+		}
+		delNames = append(delNames, d.TopicNames...)
+		for _, t := range d.Topics {
+			delTopics = append(delTopics, *t.Topic)
+		}
+	}
+
+	exp := []string{"a", "b", "c", "d", "e", "f", "g"}
+	for _, got := range [][]string{createTopics, delNames, delTopics} {
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("got topics %v != exp %v", got, exp)
+		}
+	}
+
+	if chunks := create.Chunk(0); len(chunks) != 1 || len(chunks[0].Topics) != 7 {
+		t.Errorf("got %d chunks for no max, expected 1 with all topics", len(chunks))
+	}
+	if chunks := NewPtrDeleteTopicsRequest().Chunk(2); len(chunks) != 1 {
+		t.Errorf("got %d chunks for an empty request, expected 1", len(chunks))
+	}
+}
+
+func TestValidTopicName(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		valid bool
+	}{
+		{"foo.bar-baz_1", true},
+		{strings.Repeat("a", MaxTopicNameLength), true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{strings.Repeat("a", MaxTopicNameLength+1), false},
+		{"foo bar", false},
+		{"foo/bar", false},
+		{"föo", false},
+	} {
+		if err := ValidTopicName(test.name); (err == nil) != test.valid {
+			t.Errorf("%q: got err %v, expected valid %v", test.name, err, test.valid)
+		}
+	}
+}
+
+func TestTopicNamesCollide(t *testing.T) {
+	for _, test := range []struct {
+		a, b    string
+		collide bool
+	}{
+		{"foo.bar", "foo_bar", true},
+		{"a.b_c", "a_b.c", true},
+		{"foo.bar", "foo.bar", false},
+		{"foo.bar", "foo-bar", false},
+	} {
+		if got := TopicNamesCollide(test.a, test.b); got != test.collide {
+			t.Errorf("%q, %q: got collide %v != exp %v", test.a, test.b, got, test.collide)
+		}
+	}
+}
+
+func TestBuildCreatePartitions(t *testing.T) {
+	req, err := BuildCreatePartitions(map[string]NewPartitionCount{
+		"foo": {Current: 3, NewTotal: 5, Assignments: [][]int32{{1, 2}, {2, 3}}},
+		"bar": {Current: 1, NewTotal: 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	exp := []CreatePartitionsRequestTopic{
+		{Topic: "bar", Count: 2},
+		{Topic: "foo", Count: 5, Assignment: []CreatePartitionsRequestTopicAssignment{
+			{Replicas: []int32{1, 2}},
+			{Replicas: []int32{2, 3}},
+		}},
+	}
+	if !reflect.DeepEqual(req.Topics, exp) {
+		t.Errorf("got topics %v != exp %v", req.Topics, exp)
+	}
+
+	for name, c := range map[string]NewPartitionCount{
+		"mismatched assignments": {Current: 3, NewTotal: 5, Assignments: [][]int32{{1}}},
+		"empty assignment":       {Current: 3, NewTotal: 4, Assignments: [][]int32{{}}},
+		"not increasing":         {Current: 3, NewTotal: 3},
+	} {
+		req, err := BuildCreatePartitions(map[string]NewPartitionCount{"foo": c, "bar": {Current: 1, NewTotal: 2}})
+		if err == nil || req != nil || !strings.Contains(err.Error(), "foo") {
+			t.Errorf("%s: got req %v and err %v, expected an error for foo", name, req, err)
+		}
+	}
+}
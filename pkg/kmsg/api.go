@@ -28,7 +28,13 @@ package kmsg
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kmsg/internal/kbin"
 )
@@ -148,6 +154,55 @@ type ThrottleResponse interface {
 	Throttle() (int32, bool)
 }
 
+// DecodeResponse decodes body as the response to req, returning a response
+// of req's ResponseKind at req's version. body must be the response body
+// without the response header; see ReadResponseHeader.
+func DecodeResponse(req Request, body []byte) (Response, error) {
+	resp := req.ResponseKind()
+	resp.SetVersion(req.GetVersion())
+	if err := resp.ReadFrom(body); err != nil {
+		return nil, fmt.Errorf("unable to decode %s v%d response: %w", NameForKey(req.Key()), req.GetVersion(), err)
+	}
+	return resp, nil
+}
+
+// ThrottleBackoff returns how long a client should wait before issuing its
+// next request to the broker that sent r, based on r's throttle. This returns
+// 0 if r does not have a throttle field, if the throttle is not positive, or
+// if Kafka applied the throttle before sending the response (prior to Kafka
+// 2.0), in which case the client has already been throttled.
+func ThrottleBackoff(r Response) time.Duration {
+	t, ok := r.(ThrottleResponse)
+	if !ok {
+		return 0
+	}
+	millis, afterResponse := t.Throttle()
+	if millis <= 0 || !afterResponse {
+		return 0
+	}
+	return time.Duration(millis) * time.Millisecond
+}
+
+// WasThrottled returns whether the broker throttled the request that r is a
+// response to, which is the case if r has a positive throttle. Unlike
+// ThrottleBackoff, this is true regardless of whether the throttle was
+// applied before or after the response was sent.
+//
+// A throttle alone means the request succeeded but the client exceeded a
+// quota and should slow down. A throttle combined with an error, such as
+// THROTTLING_QUOTA_EXCEEDED (89) on admin requests (KIP-599) or
+// POLICY_VIOLATION (44), means the broker rejected the request because of
+// the quota; retrying before the throttle elapses will fail again. See
+// ResponseErrorWithMessage and PartitionFailures to extract errors.
+func WasThrottled(r Response) bool {
+	t, ok := r.(ThrottleResponse)
+	if !ok {
+		return false
+	}
+	millis, _ := t.Throttle()
+	return millis > 0
+}
+
 // SetThrottleResponse sets the throttle in a response that can have a throttle
 // applied. Any kmsg interface that implements ThrottleResponse also implements
 // SetThrottleResponse.
@@ -176,7 +231,8 @@ type SetTimeoutRequest interface {
 // The default empty struct works correctly, but can be extended with the
 // NewRequestFormatter function.
 type RequestFormatter struct {
-	clientID *string
+	clientID      *string
+	correlationID int32
 }
 
 // RequestFormatterOpt applys options to a RequestFormatter.
@@ -194,6 +250,12 @@ func FormatterClientID(id string) RequestFormatterOpt {
 	return formatterOpt{func(f *RequestFormatter) { f.clientID = &id }}
 }
 
+// FormatterInitialCorrelationID sets the correlation ID used by the first
+// call to Next, overriding the default of 0.
+func FormatterInitialCorrelationID(id int32) RequestFormatterOpt {
+	return formatterOpt{func(f *RequestFormatter) { f.correlationID = id }}
+}
+
 // NewRequestFormatter returns a RequestFormatter with the opts applied.
 func NewRequestFormatter(opts ...RequestFormatterOpt) *RequestFormatter {
 	a := new(RequestFormatter)
@@ -203,6 +265,148 @@ func NewRequestFormatter(opts ...RequestFormatterOpt) *RequestFormatter {
 	return a
 }
 
+// SetClientID sets the client ID to attach to any subsequently formatted
+// request. This allows a long lived formatter to change its client ID without
+// being rebuilt.
+func (f *RequestFormatter) SetClientID(id string) {
+	f.clientID = &id
+}
+
+// Reset resets the formatter to its zero value and then applies opts, as if
+// the formatter were newly created with NewRequestFormatter. This resets the
+// correlation ID used by Next. This allows formatters to be pooled and
+// reconfigured.
+func (f *RequestFormatter) Reset(opts ...RequestFormatterOpt) {
+	*f = RequestFormatter{}
+	for _, opt := range opts {
+		opt.apply(f)
+	}
+}
+
+// HeaderIsFlexible returns whether the request header for r is flexible, that
+// is, whether the header ends with tagged fields. Request header flexibility
+// always follows the flexibility of the request body, so this is always the
+// same as r.IsFlexible(); this function exists to make the intent explicit
+// when formatting a header.
+//
+// Note that the same is not true for response headers: see
+// ResponseHeaderIsFlexible.
+func HeaderIsFlexible(r Request) bool {
+	return r.IsFlexible()
+}
+
+// ResponseHeaderIsFlexible returns whether the response header for r is
+// flexible, that is, whether the correlation ID is followed by tagged fields.
+//
+// Response header flexibility follows the flexibility of the response body
+// for every response except ApiVersions, which always uses a non-flexible
+// response header. A client does not know which ApiVersions versions a broker
+// supports when it issues the request, and a broker responding with an
+// unsupported version error must be understood by the client, so ApiVersions
+// responses never contain header tags.
+func ResponseHeaderIsFlexible(r Response) bool {
+	return r.IsFlexible() && r.Key() != ApiVersions.Int16()
+}
+
+// FrameComplete returns the total length of the frame at the start of buf,
+// including its int32 size prefix, and whether buf contains the full frame.
+// This can be used in a connection read loop to determine whether to keep
+// reading: once ok is true, buf[4:total] is the frame to pass to
+// ReadResponseHeader.
+//
+// If buf is too short to contain the size prefix, this returns 0 and false. If
+// the size prefix is negative, the frame is malformed and this returns -1
+// and false.
+func FrameComplete(buf []byte) (total int, ok bool) {
+	if len(buf) < 4 {
+		return 0, false
+	}
+	size := int32(binary.BigEndian.Uint32(buf))
+	if size < 0 {
+		return -1, false
+	}
+	total = 4 + int(size)
+	return total, len(buf) >= total
+}
+
+// ReadResponseHeader reads the response header at the start of frame, which
+// is a response as read from a connection following its int32 size prefix.
+// This returns the correlation ID in the header and the remaining response
+// body. If flexible is true, the header's tagged fields are skipped; see
+// ResponseHeaderIsFlexible.
+//
+// If frame is too short to contain the header, this returns a *ErrShort,
+// which is ErrNotEnoughData under errors.Is. Need is 4 if frame cannot hold
+// the correlation ID, and otherwise the number of bytes needed to read the
+// header tags as far as they could be parsed. A malformed tag varint is
+// reported as ErrVarintOverflow.
+func ReadResponseHeader(frame []byte, flexible bool) (correlationID int32, body []byte, err error) {
+	if len(frame) < 4 {
+		return 0, nil, &ErrShort{Need: 4, Have: len(frame)}
+	}
+	correlationID = int32(binary.BigEndian.Uint32(frame))
+	n := 4
+	if flexible {
+		if n, err = skipHeaderTags(frame, n); err != nil {
+			return 0, nil, err
+		}
+	}
+	return correlationID, frame[n:], nil
+}
+
+// skipHeaderTags skips the tagged fields starting at frame[n:] and returns the
+// offset in frame following them.
+func skipHeaderTags(frame []byte, n int) (int, error) {
+	uvarint := func() (uint64, error) {
+		v, l := binary.Uvarint(frame[n:])
+		if l == 0 {
+			return 0, &ErrShort{Need: len(frame) + 1, Have: len(frame)}
+		}
+		if l < 0 || v > 1<<32-1 {
+			return 0, ErrVarintOverflow
+		}
+		n += l
+		return v, nil
+	}
+	num, err := uvarint()
+	if err != nil {
+		return 0, err
+	}
+	for ; num > 0; num-- {
+		if _, err := uvarint(); err != nil { // tag key
+			return 0, err
+		}
+		size, err := uvarint()
+		if err != nil {
+			return 0, err
+		}
+		if uint64(len(frame)-n) < size {
+			return 0, &ErrShort{Need: n + int(size), Have: len(frame)}
+		}
+		n += int(size)
+	}
+	return n, nil
+}
+
+// MatchCorrelation returns an error if the correlation ID in the response
+// header at the start of frame is not expectedID. See ReadResponseHeader for
+// what frame and flexible are. This guards against matching a response to the
+// wrong request on a connection with multiple requests in flight.
+//
+// An error reading the header is wrapped, so that a short frame can be
+// distinguished from a mismatched correlation ID with
+// errors.Is(err, ErrNotEnoughData).
+func MatchCorrelation(frame []byte, expectedID int32, flexible bool) error {
+	id, _, err := ReadResponseHeader(frame, flexible)
+	if err != nil {
+		return fmt.Errorf("unable to read response header: %w", err)
+	}
+	if id != expectedID {
+		return fmt.Errorf("response correlation ID %d does not match expected %d", id, expectedID)
+	}
+	return nil
+}
+
 // AppendRequest appends a full message request to dst, returning the updated
 // slice. This message is the full body that needs to be written to issue a
 // Kafka request.
@@ -229,7 +433,7 @@ func (f *RequestFormatter) AppendRequest(
 
 	// The flexible tags end the request header, and then begins the
 	// request body.
-	if r.IsFlexible() {
+	if HeaderIsFlexible(r) {
 		var numTags uint8
 		dst = append(dst, numTags)
 		if numTags != 0 {
@@ -244,6 +448,31 @@ func (f *RequestFormatter) AppendRequest(
 	return dst
 }
 
+var writeBufPool = sync.Pool{New: func() interface{} { return new([]byte) }}
+
+// WriteRequest formats the request with AppendRequest into a pooled buffer and
+// writes the full, length prefixed request to w, returning the number of bytes
+// written and any write error. This is safe for concurrent use as long as w
+// is.
+func (f *RequestFormatter) WriteRequest(w io.Writer, r Request, correlationID int32) (int, error) {
+	buf := writeBufPool.Get().(*[]byte)
+	defer writeBufPool.Put(buf)
+	*buf = f.AppendRequest((*buf)[:0], r, correlationID)
+	return w.Write(*buf)
+}
+
+// Next is the same as AppendRequest, but uses the formatter's internal
+// correlation ID and then increments it. The first correlation ID is 0 unless
+// overridden with FormatterInitialCorrelationID.
+//
+// Next is not safe for concurrent use; callers must serialize calls to Next
+// on the same formatter.
+func (f *RequestFormatter) Next(dst []byte, r Request) []byte {
+	id := f.correlationID
+	f.correlationID++
+	return f.AppendRequest(dst, r, id)
+}
+
 // StringPtr is a helper to return a pointer to a string.
 func StringPtr(in string) *string {
 	return &in
@@ -404,7 +633,8 @@ func (t *Tags) Each(fn func(uint32, []byte)) {
 // Set sets a tag's key and val.
 //
 // Note that serializing tags does NOT check if the set key overlaps with an
-// existing used key. It is invalid to set a key used by Kafka itself.
+// existing used key. It is invalid to set a key used by Kafka itself; see
+// ValidateTags.
 func (t *Tags) Set(key uint32, val []byte) {
 	if t.keyvals == nil {
 		t.keyvals = make(map[uint32][]byte)
@@ -412,6 +642,56 @@ func (t *Tags) Set(key uint32, val []byte) {
 	t.keyvals[key] = val
 }
 
+// ValidateTags returns an error if any UnknownTags in m, which is a pointer
+// to a request, response, or any other struct in this package, contains a key
+// that is also the key of one of the struct's modeled tagged fields. Every
+// struct at any depth in m is checked. Each tag key can only be serialized
+// once; a struct with an unknown tag that duplicates a modeled tag is
+// rejected by the broker.
+//
+// This can be used in a proxy that captures unknown tags and sets them on
+// another message, before serializing the message.
+func ValidateTags(m interface{}) error {
+	return validateTags(reflect.ValueOf(m))
+}
+
+func validateTags(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return validateTags(v.Elem())
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := validateTags(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if f := v.FieldByName("UnknownTags"); f.IsValid() && f.Type() == reflect.TypeOf(Tags{}) && v.CanAddr() {
+			tags := f.Addr().Interface().(*Tags)
+			var dups []uint32
+			for _, key := range modeledTags(v.Addr().Interface()) {
+				if _, exists := tags.keyvals[key]; exists {
+					dups = append(dups, key)
+				}
+			}
+			if len(dups) > 0 {
+				return fmt.Errorf("%s: unknown tags duplicate modeled tag keys %v", v.Type().Name(), dups)
+			}
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if err := validateTags(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // AppendEach appends each keyval in tags to dst and returns the updated dst.
 func (t *Tags) AppendEach(dst []byte) []byte {
 	t.Each(func(key uint32, val []byte) {
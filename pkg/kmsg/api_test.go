@@ -0,0 +1,263 @@
+package kmsg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg/internal/kbin"
+)
+
+func TestRequestFormatterClientID(t *testing.T) {
+	req := NewPtrApiVersionsRequest()
+	f := NewRequestFormatter(FormatterClientID("foo"))
+	foo := f.AppendRequest(nil, req, 1)
+	if !bytes.Contains(foo, []byte("\x00\x03foo")) {
+		t.Errorf("initial request %x does not contain client ID foo", foo)
+	}
+
+	f.SetClientID("barbaz")
+	barbaz := f.AppendRequest(nil, req, 1)
+	if !bytes.Contains(barbaz, []byte("\x00\x06barbaz")) || bytes.Contains(barbaz, []byte("foo")) {
+		t.Errorf("request %x after SetClientID does not contain only client ID barbaz", barbaz)
+	}
+
+	f.Reset()
+	if got, exp := f.AppendRequest(nil, req, 1), new(RequestFormatter).AppendRequest(nil, req, 1); !bytes.Equal(got, exp) {
+		t.Errorf("got %x != exp %x after Reset", got, exp)
+	}
+	f.Reset(FormatterClientID("foo"))
+	if got := f.AppendRequest(nil, req, 1); !bytes.Equal(got, foo) {
+		t.Errorf("got %x != exp %x after Reset with client ID", got, foo)
+	}
+}
+
+func TestHeaderIsFlexible(t *testing.T) {
+	for _, test := range []struct {
+		req     Request
+		version int16
+		expReq  bool
+		expResp bool
+	}{
+		{NewPtrMetadataRequest(), 8, false, false},
+		{NewPtrMetadataRequest(), 9, true, true},
+		{NewPtrApiVersionsRequest(), 2, false, false},
+		{NewPtrApiVersionsRequest(), 3, true, false}, // ApiVersions response headers are never flexible
+	} {
+		test.req.SetVersion(test.version)
+		resp := test.req.ResponseKind()
+		resp.SetVersion(test.version)
+		name := NameForKey(test.req.Key())
+		if got := HeaderIsFlexible(test.req); got != test.expReq {
+			t.Errorf("%s v%d: got request header flexible %v != exp %v", name, test.version, got, test.expReq)
+		}
+		if got := ResponseHeaderIsFlexible(resp); got != test.expResp {
+			t.Errorf("%s v%d: got response header flexible %v != exp %v", name, test.version, got, test.expResp)
+		}
+	}
+}
+
+func TestRequestFormatterNext(t *testing.T) {
+	req := NewPtrApiVersionsRequest()
+	f := NewRequestFormatter(FormatterInitialCorrelationID(5))
+	for exp := int32(5); exp < 8; exp++ {
+		got := f.Next(nil, req)
+		if want := f.AppendRequest(nil, req, exp); !bytes.Equal(got, want) {
+			t.Errorf("got %x != exp %x", got, want)
+		}
+		if id := int32(binary.BigEndian.Uint32(got[8:])); id != exp {
+			t.Errorf("got correlation ID %d != exp %d", id, exp)
+		}
+	}
+
+	f.Reset()
+	if id := int32(binary.BigEndian.Uint32(f.Next(nil, req)[8:])); id != 0 {
+		t.Errorf("got correlation ID %d after Reset != exp 0", id)
+	}
+}
+
+func TestThrottleBackoff(t *testing.T) {
+	fetch := NewPtrFetchResponse()
+	fetch.Version = 8
+	fetch.ThrottleMillis = 250
+
+	old := NewPtrFetchResponse()
+	old.Version = 7 // throttle applied before the response
+	old.ThrottleMillis = 250
+
+	for i, test := range []struct {
+		resp Response
+		exp  time.Duration
+	}{
+		{fetch, 250 * time.Millisecond},
+		{old, 0},
+		{NewPtrFetchResponse(), 0},
+		{NewPtrSASLHandshakeResponse(), 0}, // no throttle field
+	} {
+		if got := ThrottleBackoff(test.resp); got != test.exp {
+			t.Errorf("#%d: got %v != exp %v", i, got, test.exp)
+		}
+	}
+}
+
+func TestMatchCorrelation(t *testing.T) {
+	body := []byte{0xde, 0xad}
+	plain := append(kbin.AppendInt32(nil, 7), body...)
+	flexible := append(kbin.AppendInt32(nil, 7), 0) // zero header tags
+	flexible = append(flexible, body...)
+
+	for _, test := range []struct {
+		frame    []byte
+		flexible bool
+	}{
+		{plain, false},
+		{flexible, true},
+	} {
+		id, rest, err := ReadResponseHeader(test.frame, test.flexible)
+		if err != nil || id != 7 || !bytes.Equal(rest, body) {
+			t.Errorf("flexible %v: got id %d, body %x, err %v, expected id 7 body %x", test.flexible, id, rest, err, body)
+		}
+		if err := MatchCorrelation(test.frame, 7, test.flexible); err != nil {
+			t.Errorf("flexible %v: got unexpected mismatch: %v", test.flexible, err)
+		}
+		if err := MatchCorrelation(test.frame, 8, test.flexible); err == nil {
+			t.Errorf("flexible %v: expected mismatch", test.flexible)
+		}
+	}
+
+	if err := MatchCorrelation([]byte{0, 0}, 0, false); err == nil {
+		t.Error("expected error for a short frame")
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	req := MetadataForTopics("foo")
+	req.SetVersion(7)
+
+	md := testMetadata(map[string][]int32{"foo": {1, 2}})
+	md.SetVersion(7)
+	frame := append(kbin.AppendInt32(nil, 3), md.AppendTo(nil)...)
+
+	if err := MatchCorrelation(frame, 3, ResponseHeaderIsFlexible(md)); err != nil {
+		t.Fatalf("unexpected correlation mismatch: %v", err)
+	}
+	_, body, _ := ReadResponseHeader(frame, ResponseHeaderIsFlexible(md))
+	resp, err := DecodeResponse(req, body)
+	if err != nil {
+		t.Fatalf("unable to decode: %v", err)
+	}
+	got, ok := resp.(*MetadataResponse)
+	if !ok || got.GetVersion() != 7 || !Equal(got, md) {
+		t.Errorf("got %v (metadata? %v) != exp %v", resp, ok, md)
+	}
+
+	if _, err := DecodeResponse(req, body[:len(body)-1]); err == nil {
+		t.Error("expected error decoding a truncated body")
+	}
+}
+
+func TestWasThrottled(t *testing.T) {
+	throttled := NewPtrAlterPartitionAssignmentsResponse()
+	throttled.ThrottleMillis = 100
+
+	rejected := NewPtrAlterPartitionAssignmentsResponse()
+	rejected.ThrottleMillis = 100
+	rejected.ErrorCode = 89
+
+	for _, test := range []struct {
+		name         string
+		r            Response
+		expThrottled bool
+		expCode      int16
+	}{
+		{"not throttled", NewPtrAlterPartitionAssignmentsResponse(), false, 0},
+		{"throttled only", throttled, true, 0},
+		{"throttled and rejected", rejected, true, 89},
+		{"no throttle field", NewPtrSASLHandshakeResponse(), false, 0},
+	} {
+		code, _ := ResponseErrorWithMessage(test.r)
+		if got := WasThrottled(test.r); got != test.expThrottled || code != test.expCode {
+			t.Errorf("%s: got throttled %v code %d != exp %v %d", test.name, got, code, test.expThrottled, test.expCode)
+		}
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	resp := NewPtrApiVersionsResponse()
+	resp.UnknownTags.Set(7, []byte{1})
+	if err := ValidateTags(resp); err != nil {
+		t.Errorf("got unexpected err for a unique tag: %v", err)
+	}
+
+	resp.UnknownTags.Set(2, []byte{1})
+	if err := ValidateTags(resp); err == nil {
+		t.Error("expected error for an unknown tag duplicating a modeled tag")
+	}
+
+	// Nested structs are checked against their own modeled tags.
+	fetch := testFetchResponse(nil)
+	fetch.Topics[0].UnknownTags.Set(0, []byte{1}) // topics have no tagged fields
+	if err := ValidateTags(fetch); err != nil {
+		t.Errorf("got unexpected err for an unmodeled nested tag: %v", err)
+	}
+	fetch.Topics[0].Partitions[0].UnknownTags.Set(1, []byte{1})
+	if err := ValidateTags(fetch); err == nil || !strings.Contains(err.Error(), "FetchResponseTopicPartition") {
+		t.Errorf("got err %v, expected an error for the duplicate partition tag", err)
+	}
+
+	if err := ValidateTags(NewPtrMetadataRequest()); err != nil {
+		t.Errorf("got unexpected err for no tags: %v", err)
+	}
+}
+
+func TestRequestFormatterWriteRequest(t *testing.T) {
+	f := NewRequestFormatter(FormatterClientID("cid"))
+	req := MetadataForTopics("foo")
+	req.SetVersion(9)
+
+	var buf bytes.Buffer
+	n, err := f.WriteRequest(&buf, req, 12)
+	if err != nil || n != buf.Len() {
+		t.Fatalf("got n %d and err %v, expected %d bytes written", n, err, buf.Len())
+	}
+	if exp := f.AppendRequest(nil, req, 12); !bytes.Equal(buf.Bytes(), exp) {
+		t.Errorf("got written %x != exp %x", buf.Bytes(), exp)
+	}
+	frame := buf.Bytes()
+	if size := int(binary.BigEndian.Uint32(frame)); size != len(frame)-4 {
+		t.Errorf("got frame size %d != exp %d", size, len(frame)-4)
+	}
+	if id := int32(binary.BigEndian.Uint32(frame[8:])); id != 12 {
+		t.Errorf("got correlation ID %d != exp 12", id)
+	}
+}
+
+func TestFrameComplete(t *testing.T) {
+	frame := append(kbin.AppendInt32(nil, 5), 0, 0, 0, 7, 1) // correlation ID 7 and a one byte body
+
+	for _, test := range []struct {
+		name     string
+		buf      []byte
+		expTotal int
+		expOk    bool
+	}{
+		{"empty", nil, 0, false},
+		{"partial prefix", frame[:3], 0, false},
+		{"partial body", frame[:6], 9, false},
+		{"complete", frame, 9, true},
+		{"complete with more", append(append([]byte(nil), frame...), 0, 0), 9, true},
+		{"negative size", kbin.AppendInt32(nil, -1), -1, false},
+	} {
+		total, ok := FrameComplete(test.buf)
+		if total != test.expTotal || ok != test.expOk {
+			t.Errorf("%s: got (%d, %v) != exp (%d, %v)", test.name, total, ok, test.expTotal, test.expOk)
+		}
+	}
+
+	total, _ := FrameComplete(frame)
+	if err := MatchCorrelation(frame[4:total], 7, false); err != nil {
+		t.Errorf("unexpected correlation mismatch: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package kmsg
+
+// Feature is a higher level client capability that requires a broker to
+// support a set of requests at minimum versions.
+type Feature int8
+
+const (
+	// FeatureIdempotentProduce is idempotent producing, introduced in
+	// KIP-98 (Kafka 0.11).
+	FeatureIdempotentProduce Feature = iota
+	// FeatureTransactions is transactional producing and committing,
+	// introduced in KIP-98 (Kafka 0.11).
+	FeatureTransactions
+	// FeatureIncrementalFetch is incremental fetch sessions, introduced in
+	// KIP-227 (Kafka 1.1).
+	FeatureIncrementalFetch
+	// FeatureZstd is zstd compression, introduced in KIP-110 (Kafka 2.1).
+	FeatureZstd
+	// FeatureLeaderEpochFencing is leader epoch fencing for truncation
+	// detection, introduced in KIP-320 (Kafka 2.1).
+	FeatureLeaderEpochFencing
+	// FeatureIncrementalAlterConfigs is the IncrementalAlterConfigs
+	// request, introduced in KIP-339 (Kafka 2.3).
+	FeatureIncrementalAlterConfigs
+	// FeatureStaticMembership is static group membership, introduced in
+	// KIP-345 (Kafka 2.3).
+	FeatureStaticMembership
+	// FeatureTopicIDs is fetching by topic ID rather than name,
+	// introduced in KIP-516 (Kafka 3.1).
+	FeatureTopicIDs
+)
+
+// featureKeys maps each feature to the request keys and the minimum versions
+// of those keys that are required for the feature.
+var featureKeys = map[Feature][]struct{ key, min int16 }{
+	FeatureIdempotentProduce: {
+		{int16(InitProducerID), 0},
+		{int16(Produce), 3},
+	},
+	FeatureTransactions: {
+		{int16(InitProducerID), 0},
+		{int16(Produce), 3},
+		{int16(AddPartitionsToTxn), 0},
+		{int16(AddOffsetsToTxn), 0},
+		{int16(EndTxn), 0},
+		{int16(TxnOffsetCommit), 0},
+	},
+	FeatureIncrementalFetch: {
+		{int16(Fetch), 7},
+	},
+	FeatureZstd: {
+		{int16(Produce), 7},
+		{int16(Fetch), 10},
+	},
+	FeatureLeaderEpochFencing: {
+		{int16(Fetch), 9},
+		{int16(ListOffsets), 4},
+		{int16(Metadata), 7},
+		{int16(OffsetForLeaderEpoch), 2},
+	},
+	FeatureIncrementalAlterConfigs: {
+		{int16(IncrementalAlterConfigs), 0},
+	},
+	FeatureStaticMembership: {
+		{int16(JoinGroup), 5},
+		{int16(SyncGroup), 3},
+		{int16(Heartbeat), 3},
+		{int16(LeaveGroup), 3},
+		{int16(OffsetCommit), 7},
+	},
+	FeatureTopicIDs: {
+		{int16(Metadata), 10},
+		{int16(Fetch), 13},
+	},
+}
+
+// String returns the name of the feature.
+func (f Feature) String() string {
+	switch f {
+	case FeatureIdempotentProduce:
+		return "IdempotentProduce"
+	case FeatureTransactions:
+		return "Transactions"
+	case FeatureIncrementalFetch:
+		return "IncrementalFetch"
+	case FeatureZstd:
+		return "Zstd"
+	case FeatureLeaderEpochFencing:
+		return "LeaderEpochFencing"
+	case FeatureIncrementalAlterConfigs:
+		return "IncrementalAlterConfigs"
+	case FeatureStaticMembership:
+		return "StaticMembership"
+	case FeatureTopicIDs:
+		return "TopicIDs"
+	default:
+		return "Unknown"
+	}
+}
+
+// HasFeature returns whether the broker that sent this response supports the
+// given feature, i.e. whether every request the feature requires is
+// supported at the minimum version the feature requires. Unknown features
+// are never supported.
+func (v *ApiVersionsResponse) HasFeature(f Feature) bool {
+	required, exists := featureKeys[f]
+	if !exists {
+		return false
+	}
+	for _, r := range required {
+		max, ok := v.maxFor(r.key)
+		if !ok || max < r.min {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *ApiVersionsResponse) maxFor(key int16) (int16, bool) {
+	for _, k := range v.ApiKeys {
+		if k.ApiKey == key {
+			return k.MaxVersion, true
+		}
+	}
+	return -1, false
+}
+
+// MaxFlexibleFor returns the highest version of the given key that both the
+// broker that sent this response and this package support, if that version
+// uses flexible encoding. This returns false if the broker does not support
+// the key, or if the highest mutually supported version is not flexible
+// (e.g., the broker's max version is below the version at which the request
+// became flexible).
+//
+// Flexible versions are always the highest versions of a request, so if the
+// highest mutually supported version is not flexible, no mutually supported
+// version is.
+func (v *ApiVersionsResponse) MaxFlexibleFor(key int16) (int16, bool) {
+	brokerMax, ok := v.maxFor(key)
+	if !ok {
+		return -1, false
+	}
+	r := RequestForKey(key)
+	if r == nil {
+		return -1, false
+	}
+	max := r.MaxVersion()
+	if brokerMax < max {
+		max = brokerMax
+	}
+	for _, k := range v.ApiKeys {
+		if k.ApiKey == key && k.MinVersion > max {
+			return -1, false
+		}
+	}
+	flexibleAt, ok := FlexibleAt(key)
+	if !ok || max < flexibleAt {
+		return -1, false
+	}
+	return max, true
+}
+
+// FeatureRange is the range of versions of a broker feature (KIP-584), such
+// as metadata.version.
+type FeatureRange struct {
+	Min int16
+	Max int16
+}
+
+// SupportedFeatureRanges returns the features the broker that sent this
+// response supports, mapped to the range of versions supported for each.
+// Features are only returned in v3+ responses.
+func (v *ApiVersionsResponse) SupportedFeatureRanges() map[string]FeatureRange {
+	features := make(map[string]FeatureRange, len(v.SupportedFeatures))
+	for _, f := range v.SupportedFeatures {
+		features[f.Name] = FeatureRange{Min: f.MinVersion, Max: f.MaxVersion}
+	}
+	return features
+}
+
+// FinalizedFeatureRanges returns the cluster wide finalized features, mapped
+// to the range of finalized version levels for each, along with the epoch
+// of the finalized features. The epoch is -1 if the broker does not know the
+// finalized features. Features are only returned in v3+ responses.
+func (v *ApiVersionsResponse) FinalizedFeatureRanges() (map[string]FeatureRange, int64) {
+	features := make(map[string]FeatureRange, len(v.FinalizedFeatures))
+	for _, f := range v.FinalizedFeatures {
+		features[f.Name] = FeatureRange{Min: f.MinVersionLevel, Max: f.MaxVersionLevel}
+	}
+	return features, v.FinalizedFeaturesEpoch
+}
+
+// NewHandshakeApiVersions returns an ApiVersionsRequest pinned to v0, which
+// is safe to issue as the first request on a connection before knowing what
+// versions the broker supports. v0 is not flexible, meaning the request has no
+// tagged fields that an old broker could not parse.
+//
+// A broker that supports ApiVersions v3+ responds to any version it does not
+// support with UNSUPPORTED_VERSION and its supported versions at v0, so
+// issuing a higher version first also works against modern brokers; starting
+// at v0 avoids relying on that.
+func NewHandshakeApiVersions() *ApiVersionsRequest {
+	req := NewPtrApiVersionsRequest()
+	req.SetVersion(0)
+	return req
+}
@@ -0,0 +1,118 @@
+package kmsg
+
+import "testing"
+
+// apiVersions returns a response supporting the given key/max version pairs.
+func apiVersions(keyMaxes ...int16) *ApiVersionsResponse {
+	resp := NewPtrApiVersionsResponse()
+	for i := 0; i < len(keyMaxes); i += 2 {
+		k := NewApiVersionsResponseApiKey()
+		k.ApiKey = keyMaxes[i]
+		k.MaxVersion = keyMaxes[i+1]
+		resp.ApiKeys = append(resp.ApiKeys, k)
+	}
+	return resp
+}
+
+func TestHasFeature(t *testing.T) {
+	// Kafka 0.11 supports idempotency and transactions, but not
+	// incremental fetch sessions or KIP-320.
+	v011 := apiVersions(0, 3, 1, 5, 2, 2, 3, 4, 22, 0, 23, 0, 24, 0, 25, 0, 26, 0, 28, 0)
+	// A response with InitProducerID but an old produce.
+	oldProduce := apiVersions(0, 2, 22, 0)
+
+	for _, test := range []struct {
+		resp *ApiVersionsResponse
+		f    Feature
+		exp  bool
+	}{
+		{v011, FeatureIdempotentProduce, true},
+		{v011, FeatureTransactions, true},
+		{v011, FeatureIncrementalFetch, false},
+		{v011, FeatureLeaderEpochFencing, false},
+		{oldProduce, FeatureIdempotentProduce, false},
+		{oldProduce, FeatureTransactions, false},
+		{v011, Feature(-1), false},
+	} {
+		if got := test.resp.HasFeature(test.f); got != test.exp {
+			t.Errorf("%s: got %v != exp %v", test.f, got, test.exp)
+		}
+	}
+}
+
+func TestMaxFlexibleFor(t *testing.T) {
+	// Metadata is flexible at v9, and Produce at v9.
+	resp := apiVersions(3, 8, 0, 100, 18, 3)
+	for _, test := range []struct {
+		key     int16
+		exp     int16
+		expOk   bool
+		flexAt  int16
+		plainOk bool
+	}{
+		{3, -1, false, 9, true},                                 // broker max below flexible
+		{0, NewPtrProduceRequest().MaxVersion(), true, 9, true}, // clamped to our max
+		{18, 3, true, 3, true},
+		{1, -1, false, 12, false}, // broker does not support fetch
+	} {
+		got, ok := resp.MaxFlexibleFor(test.key)
+		if got != test.exp || ok != test.expOk {
+			t.Errorf("key %d: got %d %v != exp %d %v", test.key, got, ok, test.exp, test.expOk)
+		}
+		if max, ok := resp.maxFor(test.key); ok != test.plainOk || (ok && max < 0) {
+			t.Errorf("key %d: got plain max %d %v, expected usable %v", test.key, max, ok, test.plainOk)
+		}
+		if at, _ := FlexibleAt(test.key); at != test.flexAt {
+			t.Errorf("key %d: got flexible at %d != exp %d", test.key, at, test.flexAt)
+		}
+	}
+
+	resp.ApiKeys[2].MinVersion = 4
+	if _, ok := resp.MaxFlexibleFor(18); ok {
+		t.Error("expected no flexible version when broker min is above our max")
+	}
+	if _, ok := FlexibleAt(-1); ok {
+		t.Error("expected unknown key to not be flexible")
+	}
+}
+
+func TestFeatureRanges(t *testing.T) {
+	resp := apiVersions(18, 3)
+	resp.SetVersion(3)
+	sf := NewApiVersionsResponseSupportedFeature()
+	sf.Name, sf.MinVersion, sf.MaxVersion = "metadata.version", 1, 14
+	resp.SupportedFeatures = append(resp.SupportedFeatures, sf)
+	ff := NewApiVersionsResponseFinalizedFeature()
+	ff.Name, ff.MinVersionLevel, ff.MaxVersionLevel = "metadata.version", 7, 7
+	resp.FinalizedFeatures = append(resp.FinalizedFeatures, ff)
+	resp.FinalizedFeaturesEpoch = 12
+
+	got := NewPtrApiVersionsResponse()
+	got.SetVersion(3)
+	if err := got.ReadFrom(resp.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read response: %v", err)
+	}
+
+	supported := got.SupportedFeatureRanges()
+	if len(supported) != 1 || supported["metadata.version"] != (FeatureRange{1, 14}) {
+		t.Errorf("got supported %v != exp metadata.version [1, 14]", supported)
+	}
+	finalized, epoch := got.FinalizedFeatureRanges()
+	if len(finalized) != 1 || finalized["metadata.version"] != (FeatureRange{7, 7}) || epoch != 12 {
+		t.Errorf("got finalized %v at epoch %d != exp metadata.version [7, 7] at epoch 12", finalized, epoch)
+	}
+
+	if finalized, epoch := NewPtrApiVersionsResponse().FinalizedFeatureRanges(); len(finalized) != 0 || epoch != -1 {
+		t.Errorf("got finalized %v at epoch %d for an empty response, expected none at epoch -1", finalized, epoch)
+	}
+}
+
+func TestNewHandshakeApiVersions(t *testing.T) {
+	req := NewHandshakeApiVersions()
+	if req.GetVersion() != 0 || req.IsFlexible() || HeaderIsFlexible(req) {
+		t.Errorf("got version %d flexible %v, expected v0 and not flexible", req.GetVersion(), req.IsFlexible())
+	}
+	if body := req.AppendTo(nil); len(body) != 0 {
+		t.Errorf("got v0 body %x, expected empty", body)
+	}
+}
@@ -0,0 +1,24 @@
+package kmsg
+
+// AllPartitionStates returns every partition state in the request, smoothing
+// over the v2 switch from a flat PartitionStates array to partition states
+// nested in TopicStates. For v2+ requests, the returned partition states are
+// copies with Topic set from their enclosing topic state, since the nested
+// partition states do not serialize their topic.
+//
+// LeaderAndISR is a broker internal request that clients do not send; this is
+// useful for tooling that decodes controller traffic.
+func (v *LeaderAndISRRequest) AllPartitionStates() []LeaderAndISRRequestTopicPartition {
+	if v.Version < 2 {
+		return append([]LeaderAndISRRequestTopicPartition(nil), v.PartitionStates...)
+	}
+	var states []LeaderAndISRRequestTopicPartition
+	for i := range v.TopicStates {
+		t := &v.TopicStates[i]
+		for _, p := range t.PartitionStates {
+			p.Topic = t.Topic
+			states = append(states, p)
+		}
+	}
+	return states
+}
@@ -0,0 +1,49 @@
+package kmsg
+
+import "testing"
+
+func TestLeaderAndISRAllPartitionStates(t *testing.T) {
+	req := NewPtrLeaderAndISRRequest()
+	req.SetVersion(5)
+	for _, topic := range []string{"foo", "bar"} {
+		ts := NewLeaderAndISRRequestTopicState()
+		ts.Topic = topic
+		for p := int32(0); p < 2; p++ {
+			ps := NewLeaderAndISRRequestTopicPartition()
+			ps.Partition = p
+			ps.Leader = p + 1
+			ps.LeaderEpoch = 3
+			ps.ISR = []int32{p + 1, p + 2}
+			ps.Replicas = []int32{p + 1, p + 2, p + 3}
+			ts.PartitionStates = append(ts.PartitionStates, ps)
+		}
+		req.TopicStates = append(req.TopicStates, ts)
+	}
+
+	var got LeaderAndISRRequest
+	got.SetVersion(5)
+	if err := got.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read request: %v", err)
+	}
+	states := got.AllPartitionStates()
+	if len(states) != 4 {
+		t.Fatalf("got %d partition states != exp 4", len(states))
+	}
+	for i, s := range states {
+		expTopic, expPartition := []string{"foo", "bar"}[i/2], int32(i%2)
+		if s.Topic != expTopic || s.Partition != expPartition || s.Leader != expPartition+1 ||
+			s.LeaderEpoch != 3 || len(s.ISR) != 2 || len(s.Replicas) != 3 {
+			t.Errorf("state %d: got unexpected %v", i, s)
+		}
+	}
+	if got.TopicStates[0].PartitionStates[0].Topic != "" {
+		t.Error("flattening unexpectedly modified the request")
+	}
+
+	v1 := NewPtrLeaderAndISRRequest()
+	v1.SetVersion(1)
+	v1.PartitionStates = []LeaderAndISRRequestTopicPartition{{Topic: "foo", Partition: 1}}
+	if states := v1.AllPartitionStates(); len(states) != 1 || states[0].Topic != "foo" {
+		t.Errorf("v1: got unexpected %v", states)
+	}
+}
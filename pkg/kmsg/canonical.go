@@ -0,0 +1,99 @@
+package kmsg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/fnv"
+	"reflect"
+)
+
+// Canonicalize converts, in place, every empty non-nil slice in m to nil if
+// the slice serializes identically whether it is nil or empty. This is useful
+// in tests to compare messages with reflect.DeepEqual: a non-nullable array
+// has a length of zero on the wire whether it is nil or empty, but the two
+// are not deeply equal. Nullable arrays and bytes (where nil is serialized
+// as null) are left as is.
+//
+// m must be a pointer to a message that can be serialized with AppendTo,
+// such as a Request, Response, or Record; otherwise, this does nothing.
+// Whether a slice is serialized identically is determined by serializing m
+// at its current version, so slices in fields that do not exist at the
+// current version are always converted to nil.
+func Canonicalize(m interface{}) {
+	e, ok := m.(interface{ AppendTo([]byte) []byte })
+	if !ok {
+		return
+	}
+	rv := reflect.ValueOf(m)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return
+	}
+	canonicalize(e.AppendTo, rv.Elem())
+}
+
+func canonicalize(appendTo func([]byte) []byte, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			canonicalize(appendTo, v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				canonicalize(appendTo, f)
+			}
+		}
+	case reflect.Slice:
+		if v.Len() > 0 {
+			if v.Type().Elem().Kind() != reflect.Uint8 {
+				for i := 0; i < v.Len(); i++ {
+					canonicalize(appendTo, v.Index(i))
+				}
+			}
+			return
+		}
+		if v.IsNil() {
+			return
+		}
+		before := appendTo(nil)
+		orig := reflect.ValueOf(v.Interface())
+		v.Set(reflect.Zero(v.Type()))
+		if !bytes.Equal(before, appendTo(nil)) {
+			v.Set(orig)
+		}
+	}
+}
+
+// Equal returns whether a and b are the same type of message and serialize
+// identically, meaning they are equal as far as Kafka is concerned. Unlike
+// reflect.DeepEqual, this considers nil and empty non-nullable slices equal
+// and ignores fields that do not exist at the messages' version.
+//
+// If either a or b cannot be serialized with AppendTo, this falls back to
+// reflect.DeepEqual.
+func Equal(a, b interface{}) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	ea, aok := a.(interface{ AppendTo([]byte) []byte })
+	eb, bok := b.(interface{ AppendTo([]byte) []byte })
+	if !aok || !bok {
+		return reflect.DeepEqual(a, b)
+	}
+	return bytes.Equal(ea.AppendTo(nil), eb.AppendTo(nil))
+}
+
+// Fingerprint returns a non-cryptographic 64-bit hash of the request's key,
+// version, and serialized body. Requests that serialize identically at the
+// same version have the same fingerprint, making this useful to coalesce or
+// cache duplicate requests. The request header, including the correlation ID
+// and client ID, is not part of the fingerprint.
+func Fingerprint(r Request) uint64 {
+	var kv [4]byte
+	binary.BigEndian.PutUint16(kv[:], uint16(r.Key()))
+	binary.BigEndian.PutUint16(kv[2:], uint16(r.GetVersion()))
+	h := fnv.New64a()
+	h.Write(kv[:])
+	h.Write(r.AppendTo(nil))
+	return h.Sum64()
+}
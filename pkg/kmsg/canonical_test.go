@@ -0,0 +1,66 @@
+package kmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	a := NewPtrCreateTopicsRequest()
+	a.Version = 5
+	topic := NewCreateTopicsRequestTopic()
+	topic.Topic = "foo"
+	topic.ReplicaAssignment = []CreateTopicsRequestTopicReplicaAssignment{}
+	a.Topics = append(a.Topics, topic)
+
+	b := NewPtrCreateTopicsRequest()
+	b.Version = 5
+	topic.ReplicaAssignment = nil
+	b.Topics = append(b.Topics, topic)
+
+	if reflect.DeepEqual(a, b) {
+		t.Fatal("expected nil and empty arrays to differ before canonicalizing")
+	}
+	if !Equal(a, b) {
+		t.Error("expected nil and empty arrays to be Equal")
+	}
+	Canonicalize(a)
+	Canonicalize(b)
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("got %v != exp %v after canonicalizing", a, b)
+	}
+
+	// Metadata topics are nullable at v1+: nil means all topics, while
+	// empty means no topics, so empty must not be converted.
+	m := NewPtrMetadataRequest()
+	m.Version = 4
+	m.Topics = []MetadataRequestTopic{}
+	Canonicalize(m)
+	if m.Topics == nil {
+		t.Error("nullable empty array was unexpectedly converted to nil")
+	}
+	if Equal(m, &MetadataRequest{Version: 4}) {
+		t.Error("nil and empty nullable arrays are unexpectedly Equal")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := MetadataForTopics("foo", "bar")
+	b := MetadataForTopics("foo", "bar")
+	a.SetVersion(9)
+	b.SetVersion(9)
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Error("got different fingerprints for equal requests")
+	}
+
+	c := MetadataForTopics("foo", "baz")
+	c.SetVersion(9)
+	if Fingerprint(a) == Fingerprint(c) {
+		t.Error("got equal fingerprints for requests with different topics")
+	}
+
+	b.SetVersion(8)
+	if Fingerprint(a) == Fingerprint(b) {
+		t.Error("got equal fingerprints for requests at different versions")
+	}
+}
@@ -0,0 +1,139 @@
+package kmsg
+
+import "encoding/binary"
+
+// IsIdempotentRequest returns whether issuing r multiple times has the same
+// effect as issuing it once, meaning a generic retry layer can blindly retry r
+// on a timeout or a broken connection.
+//
+// Requests that only read state (Metadata, Fetch, ListOffsets, Describe*,
+// List*, etc.) are always idempotent. Produce requests are idempotent only if
+// every record batch in the request is a v2 batch with a producer ID set:
+// Kafka deduplicates these by their producer ID, epoch, and sequence number.
+// Produce requests without a producer ID, and every other request that
+// mutates state (commits, group joins, transactional requests, admin
+// requests, ...), are conservatively considered not idempotent.
+//
+// Note that Metadata requests can have the side effect of creating topics if
+// AllowAutoTopicCreation is true, but creating an existing topic is a no-op,
+// so these are still considered idempotent.
+func IsIdempotentRequest(r Request) bool {
+	switch Key(r.Key()) {
+	case Fetch,
+		ListOffsets,
+		Metadata,
+		OffsetFetch,
+		FindCoordinator,
+		DescribeGroups,
+		ListGroups,
+		ApiVersions,
+		OffsetForLeaderEpoch,
+		DescribeACLs,
+		DescribeConfigs,
+		DescribeLogDirs,
+		DescribeDelegationToken,
+		ListPartitionReassignments,
+		DescribeClientQuotas,
+		DescribeUserSCRAMCredentials,
+		DescribeQuorum,
+		DescribeCluster,
+		DescribeProducers,
+		DescribeTransactions,
+		ListTransactions:
+		return true
+
+	case Produce:
+		req, ok := r.(*ProduceRequest)
+		if !ok {
+			return false
+		}
+		for i := range req.Topics {
+			for j := range req.Topics[i].Partitions {
+				if !isIdempotentRecordSet(req.Topics[i].Partitions[j].Records) {
+					return false
+				}
+			}
+		}
+		return true
+
+	default:
+		return false
+	}
+}
+
+// isIdempotentRecordSet returns whether every batch in the given record set
+// is a v2 batch with a producer ID. The magic byte is at offset 16 of a batch,
+// and the producer ID is at offset 43.
+func isIdempotentRecordSet(in []byte) bool {
+	if len(in) == 0 {
+		return false
+	}
+	for len(in) > 0 {
+		if len(in) < 51 || in[16] != 2 {
+			return false
+		}
+		if int64(binary.BigEndian.Uint64(in[43:])) < 0 {
+			return false
+		}
+		length := 12 + int(int32(binary.BigEndian.Uint32(in[8:])))
+		if length < 61 || length > len(in) {
+			return false
+		}
+		in = in[length:]
+	}
+	return true
+}
+
+// IsBrokerInternal returns whether r is a broker internal request, that is, a
+// request that is only sent between brokers or between controllers and
+// brokers. Normal clients never send these requests, and a proxy may want to
+// reject them.
+//
+// The broker internal requests are the controller to broker requests
+// (LeaderAndISR, StopReplica, UpdateMetadata), the broker to controller
+// requests (ControlledShutdown, AlterPartition, BrokerRegistration,
+// BrokerHeartbeat, AllocateProducerIDs, Envelope), the transaction
+// coordinator's WriteTxnMarkers, and the KRaft quorum requests (Vote,
+// BeginQuorumEpoch, EndQuorumEpoch, FetchSnapshot).
+func IsBrokerInternal(r Request) bool {
+	switch Key(r.Key()) {
+	case LeaderAndISR,
+		StopReplica,
+		UpdateMetadata,
+		ControlledShutdown,
+		WriteTxnMarkers,
+		Vote,
+		BeginQuorumEpoch,
+		EndQuorumEpoch,
+		AlterPartition,
+		Envelope,
+		FetchSnapshot,
+		BrokerRegistration,
+		BrokerHeartbeat,
+		AllocateProducerIDs:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanCoalesce returns whether a and b can be merged into a single request
+// with the same effect as issuing both. This is conservative: both requests
+// must be the same key at the same version, and the key must be one that
+// this package knows how to merge. Currently, only Metadata requests with the
+// same auto topic creation and authorized operations flags can be coalesced,
+// with CoalesceMetadata.
+func CanCoalesce(a, b Request) bool {
+	if a.Key() != b.Key() || a.GetVersion() != b.GetVersion() {
+		return false
+	}
+	switch a := a.(type) {
+	case *MetadataRequest:
+		b, ok := b.(*MetadataRequest)
+		return ok &&
+			a.AllowAutoTopicCreation == b.AllowAutoTopicCreation &&
+			a.IncludeClusterAuthorizedOperations == b.IncludeClusterAuthorizedOperations &&
+			a.IncludeTopicAuthorizedOperations == b.IncludeTopicAuthorizedOperations
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package kmsg
+
+import "testing"
+
+func TestIsIdempotentRequest(t *testing.T) {
+	batch := func(producerID int64) []byte {
+		b := NewRecordBatch()
+		b.Length = 49
+		b.Magic = 2
+		b.ProducerID = producerID
+		return b.AppendTo(nil)
+	}
+	produce := func(batches ...[]byte) Request {
+		req := NewPtrProduceRequest()
+		for _, b := range batches {
+			p := NewProduceRequestTopicPartition()
+			p.Records = b
+			t := NewProduceRequestTopic()
+			t.Partitions = append(t.Partitions, p)
+			req.Topics = append(req.Topics, t)
+		}
+		return req
+	}
+
+	for i, test := range []struct {
+		req Request
+		exp bool
+	}{
+		{NewPtrMetadataRequest(), true},
+		{NewPtrFetchRequest(), true},
+		{NewPtrListOffsetsRequest(), true},
+		{NewPtrDescribeConfigsRequest(), true},
+
+		{NewPtrOffsetCommitRequest(), false},
+		{NewPtrJoinGroupRequest(), false},
+		{NewPtrEndTxnRequest(), false},
+		{NewPtrCreateTopicsRequest(), false},
+
+		{produce(batch(3)), true},
+		{produce(batch(3), batch(4)), true},
+		{produce(batch(-1)), false},
+		{produce(batch(3), batch(-1)), false},
+		{produce(append(batch(3), batch(-1)...)), false},
+		{produce(batch(3)[:40]), false},
+	} {
+		if got := IsIdempotentRequest(test.req); got != test.exp {
+			t.Errorf("#%d (%s): got %v != exp %v", i, NameForKey(test.req.Key()), got, test.exp)
+		}
+	}
+}
+
+func TestIsBrokerInternal(t *testing.T) {
+	for i, test := range []struct {
+		req Request
+		exp bool
+	}{
+		{NewPtrLeaderAndISRRequest(), true},
+		{NewPtrStopReplicaRequest(), true},
+		{NewPtrUpdateMetadataRequest(), true},
+		{NewPtrWriteTxnMarkersRequest(), true},
+		{NewPtrControlledShutdownRequest(), true},
+		{NewPtrVoteRequest(), true},
+
+		{NewPtrProduceRequest(), false},
+		{NewPtrMetadataRequest(), false},
+		{NewPtrCreateTopicsRequest(), false},
+		{NewPtrDescribeQuorumRequest(), false},
+	} {
+		if got := IsBrokerInternal(test.req); got != test.exp {
+			t.Errorf("#%d (%s): got %v != exp %v", i, NameForKey(test.req.Key()), got, test.exp)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package kmsg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Compression codecs, as encoded in the low three bits of RecordBatch
+// attributes (and the low two bits of MessageV0 and MessageV1 attributes).
+const (
+	CodecNone   int8 = 0
+	CodecGzip   int8 = 1
+	CodecSnappy int8 = 2
+	CodecLz4    int8 = 3
+	CodecZstd   int8 = 4
+)
+
+var codecs = struct {
+	mu           sync.RWMutex
+	compressor   map[int8]func([]byte) ([]byte, error)
+	decompressor map[int8]func([]byte) ([]byte, error)
+}{
+	compressor: map[int8]func([]byte) ([]byte, error){
+		CodecGzip: gzipCompress,
+	},
+	decompressor: map[int8]func([]byte) ([]byte, error){
+		CodecGzip: gunzip,
+	},
+}
+
+// RegisterCompressor registers a function to compress data with the given
+// codec, replacing any prior function for the codec.
+//
+// As with RegisterDecompressor, only gzip compression is registered by
+// default.
+func RegisterCompressor(codec int8, fn func([]byte) ([]byte, error)) {
+	codecs.mu.Lock()
+	defer codecs.mu.Unlock()
+	codecs.compressor[codec] = fn
+}
+
+// RegisterDecompressor registers a function to decompress data that was
+// compressed with the given codec, replacing any prior function for the codec.
+//
+// This package does not depend on any external packages, and thus only gzip
+// decompression is registered by default. To decompress snappy, lz4, or zstd
+// data, you must register a decompressor for those codecs. Note that Kafka
+// uses the xerial framing for snappy and the lz4 frame format for lz4.
+func RegisterDecompressor(codec int8, fn func([]byte) ([]byte, error)) {
+	codecs.mu.Lock()
+	defer codecs.mu.Unlock()
+	codecs.decompressor[codec] = fn
+}
+
+// Compress compresses src using the compressor registered for codec.
+// CodecNone returns src as is.
+func Compress(codec int8, src []byte) ([]byte, error) {
+	if codec == CodecNone {
+		return src, nil
+	}
+	codecs.mu.RLock()
+	fn := codecs.compressor[codec]
+	codecs.mu.RUnlock()
+	if fn == nil {
+		return nil, fmt.Errorf("no compressor registered for codec %d", codec)
+	}
+	return fn(src)
+}
+
+// Decompress decompresses src using the decompressor registered for codec.
+// CodecNone returns src as is.
+func Decompress(codec int8, src []byte) ([]byte, error) {
+	if codec == CodecNone {
+		return src, nil
+	}
+	codecs.mu.RLock()
+	fn := codecs.decompressor[codec]
+	codecs.mu.RUnlock()
+	if fn == nil {
+		return nil, fmt.Errorf("no decompressor registered for codec %d", codec)
+	}
+	return fn(src)
+}
+
+func gunzip(src []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func gzipCompress(src []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,65 @@
+package kmsg
+
+// IsDefaultConfig returns whether this config entry is using its default
+// value, i.e. whether the value has not been set by an operator.
+//
+// For v0 responses, this returns the IsDefault field. For v1+ responses, the
+// IsDefault field is no longer sent and this returns whether the Source is
+// DEFAULT_CONFIG.
+func (v *DescribeConfigsResponseResourceConfig) IsDefaultConfig() bool {
+	if v.Source == -1 {
+		return v.IsDefault
+	}
+	return v.Source == ConfigSourceDefaultConfig
+}
+
+// RedactSensitive sets the value of every sensitive config entry in the
+// response to null, in place, so that the response can be safely logged.
+// Brokers already return null for sensitive values, but a response may have
+// been populated from another source (such as a local cache). The values of
+// synonyms of sensitive entries are also set to null.
+func (v *DescribeConfigsResponse) RedactSensitive() {
+	for i := range v.Resources {
+		r := &v.Resources[i]
+		for j := range r.Configs {
+			c := &r.Configs[j]
+			if !c.IsSensitive {
+				continue
+			}
+			c.Value = nil
+			for k := range c.ConfigSynonyms {
+				c.ConfigSynonyms[k].Value = nil
+			}
+		}
+	}
+}
+
+// ConfigResource is a resource to describe with BuildDescribeConfigs.
+type ConfigResource struct {
+	// Type is the type of the resource, e.g. ConfigResourceTypeTopic or
+	// ConfigResourceTypeBroker.
+	Type ConfigResourceType
+	// Name is the name of the resource: a topic name for topics, or a
+	// broker ID (or empty, for the cluster wide default) for brokers.
+	Name string
+	// ConfigKeys are the config keys to describe. If nil, all keys are
+	// described.
+	ConfigKeys []string
+}
+
+// BuildDescribeConfigs returns a DescribeConfigsRequest describing the given
+// resources, which can be of mixed types. Resources are kept in the order
+// given.
+func BuildDescribeConfigs(resources []ConfigResource) *DescribeConfigsRequest {
+	req := NewPtrDescribeConfigsRequest()
+	for _, r := range resources {
+		rr := NewDescribeConfigsRequestResource()
+		rr.ResourceType = r.Type
+		rr.ResourceName = r.Name
+		if r.ConfigKeys != nil {
+			rr.ConfigNames = append([]string{}, r.ConfigKeys...)
+		}
+		req.Resources = append(req.Resources, rr)
+	}
+	return req
+}
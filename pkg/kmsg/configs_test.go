@@ -0,0 +1,103 @@
+package kmsg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConfigSourceString(t *testing.T) {
+	for _, test := range []struct {
+		source ConfigSource
+		exp    string
+	}{
+		{-1, "UNKNOWN"},
+		{ConfigSourceUnknown, "UNKNOWN"},
+		{ConfigSourceDynamicTopicConfig, "DYNAMIC_TOPIC_CONFIG"},
+		{ConfigSourceDynamicBrokerConfig, "DYNAMIC_BROKER_CONFIG"},
+		{ConfigSourceDynamicDefaultBrokerConfig, "DYNAMIC_DEFAULT_BROKER_CONFIG"},
+		{ConfigSourceStaticBrokerConfig, "STATIC_BROKER_CONFIG"},
+		{ConfigSourceDefaultConfig, "DEFAULT_CONFIG"},
+		{ConfigSourceDynamicBrokerLoggerConfig, "DYNAMIC_BROKER_LOGGER_CONFIG"},
+	} {
+		if got := test.source.String(); got != test.exp {
+			t.Errorf("source %d: got %s != exp %s", test.source, got, test.exp)
+		}
+	}
+}
+
+func TestIsDefaultConfig(t *testing.T) {
+	c := NewDescribeConfigsResponseResourceConfig()
+	if c.IsDefaultConfig() {
+		t.Error("v0 entry without IsDefault: got default, expected not")
+	}
+	c.IsDefault = true
+	if !c.IsDefaultConfig() {
+		t.Error("v0 entry with IsDefault: got not default, expected default")
+	}
+
+	c = NewDescribeConfigsResponseResourceConfig()
+	for source, exp := range map[ConfigSource]bool{
+		ConfigSourceDefaultConfig:       true,
+		ConfigSourceStaticBrokerConfig:  false,
+		ConfigSourceDynamicTopicConfig:  false,
+		ConfigSourceDynamicBrokerConfig: false,
+	} {
+		c.Source = source
+		if got := c.IsDefaultConfig(); got != exp {
+			t.Errorf("source %s: got default %v != exp %v", source, got, exp)
+		}
+	}
+}
+
+func TestRedactSensitive(t *testing.T) {
+	resp := NewPtrDescribeConfigsResponse()
+	r := NewDescribeConfigsResponseResource()
+	for _, c := range []struct {
+		name      string
+		value     string
+		sensitive bool
+	}{
+		{"retention.ms", "1000", false},
+		{"sasl.jaas.config", "secret", true},
+	} {
+		rc := NewDescribeConfigsResponseResourceConfig()
+		rc.Name = c.name
+		rc.Value = StringPtr(c.value)
+		rc.IsSensitive = c.sensitive
+		syn := NewDescribeConfigsResponseResourceConfigConfigSynonym()
+		syn.Value = StringPtr(c.value)
+		rc.ConfigSynonyms = append(rc.ConfigSynonyms, syn)
+		r.Configs = append(r.Configs, rc)
+	}
+	resp.Resources = append(resp.Resources, r)
+
+	resp.RedactSensitive()
+
+	plain, sensitive := resp.Resources[0].Configs[0], resp.Resources[0].Configs[1]
+	if plain.Value == nil || *plain.Value != "1000" || plain.ConfigSynonyms[0].Value == nil {
+		t.Errorf("non-sensitive entry was unexpectedly modified: %v", plain)
+	}
+	if sensitive.Value != nil || sensitive.ConfigSynonyms[0].Value != nil {
+		t.Errorf("sensitive entry was not redacted: %v", sensitive)
+	}
+}
+
+func TestBuildDescribeConfigs(t *testing.T) {
+	req := BuildDescribeConfigs([]ConfigResource{
+		{Type: ConfigResourceTypeTopic, Name: "foo", ConfigKeys: []string{"retention.ms"}},
+		{Type: ConfigResourceTypeBroker, Name: "1"},
+	})
+	raw := req.AppendTo(nil) // v0
+
+	// v0: resources array length, then for each resource its int8 type,
+	// int16 length prefixed name, and nullable config names array.
+	exp := []byte{
+		0, 0, 0, 2,
+		2, 0, 3, 'f', 'o', 'o', 0, 0, 0, 1, 0, 12,
+	}
+	exp = append(exp, "retention.ms"...)
+	exp = append(exp, 4, 0, 1, '1', 0xff, 0xff, 0xff, 0xff)
+	if !bytes.Equal(raw, exp) {
+		t.Errorf("got %x != exp %x", raw, exp)
+	}
+}
@@ -0,0 +1,71 @@
+package kmsg
+
+import "fmt"
+
+// Key types for FindCoordinatorRequest.CoordinatorType.
+const (
+	// CoordinatorKeyGroup is the key type to find the coordinator of a
+	// group, where the key is the group ID.
+	CoordinatorKeyGroup int8 = 0
+	// CoordinatorKeyTransaction is the key type to find the coordinator of
+	// a transactional producer, where the key is the transactional ID.
+	CoordinatorKeyTransaction int8 = 1
+)
+
+// ValidCoordinatorKeyType returns whether t is CoordinatorKeyGroup or
+// CoordinatorKeyTransaction.
+func ValidCoordinatorKeyType(t int8) bool {
+	return t == CoordinatorKeyGroup || t == CoordinatorKeyTransaction
+}
+
+// BuildFindCoordinator returns a FindCoordinatorRequest for the given keys of
+// the given key type, which must be CoordinatorKeyGroup or
+// CoordinatorKeyTransaction. This returns an error if the key type is not
+// valid; see ValidCoordinatorKeyType.
+//
+// FindCoordinator v4 switched from a single CoordinatorKey to a batched
+// CoordinatorKeys array. This function populates both fields so that the
+// request can be issued at any version: v4+ requests all keys, while v0-v3
+// requests only the first key. If you are issuing the request at v0-v3 with
+// more than one key, you must split the request yourself (kgo does this
+// automatically).
+func BuildFindCoordinator(keyType int8, keys ...string) (*FindCoordinatorRequest, error) {
+	if !ValidCoordinatorKeyType(keyType) {
+		return nil, fmt.Errorf("invalid coordinator key type %d", keyType)
+	}
+	req := NewPtrFindCoordinatorRequest()
+	req.CoordinatorType = keyType
+	if len(keys) > 0 {
+		req.CoordinatorKey = keys[0]
+	}
+	req.CoordinatorKeys = append(req.CoordinatorKeys, keys...)
+	return req, nil
+}
+
+// CoordinatorFor returns the coordinator for the given key, smoothing over the
+// v4 switch from a single top level coordinator to a batched Coordinators
+// array. The returned ErrorCode can be converted to an error with
+// kerr.ErrorForCode.
+//
+// For v4+ responses, this returns false if the key is not in the response.
+// Responses prior to v4 do not contain the key: the response is for the single
+// key that was requested, and this always returns the top level fields with
+// the Key set to the input key.
+func (v *FindCoordinatorResponse) CoordinatorFor(key string) (FindCoordinatorResponseCoordinator, bool) {
+	if v.Version < 4 {
+		c := NewFindCoordinatorResponseCoordinator()
+		c.Key = key
+		c.NodeID = v.NodeID
+		c.Host = v.Host
+		c.Port = v.Port
+		c.ErrorCode = v.ErrorCode
+		c.ErrorMessage = v.ErrorMessage
+		return c, true
+	}
+	for _, c := range v.Coordinators {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return FindCoordinatorResponseCoordinator{}, false
+}
@@ -0,0 +1,81 @@
+package kmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildFindCoordinator(t *testing.T) {
+	req, err := BuildFindCoordinator(CoordinatorKeyTransaction, "foo", "bar")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	// At v3, only the first key is serialized.
+	req.SetVersion(3)
+	var v3 FindCoordinatorRequest
+	v3.SetVersion(3)
+	if err := v3.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read v3: %v", err)
+	}
+	if v3.CoordinatorKey != "foo" || v3.CoordinatorType != 1 || len(v3.CoordinatorKeys) != 0 {
+		t.Errorf("v3: got unexpected key %q, type %d, keys %v", v3.CoordinatorKey, v3.CoordinatorType, v3.CoordinatorKeys)
+	}
+
+	// At v4, all keys are serialized.
+	req.SetVersion(4)
+	var v4 FindCoordinatorRequest
+	v4.SetVersion(4)
+	if err := v4.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read v4: %v", err)
+	}
+	if !reflect.DeepEqual(v4.CoordinatorKeys, []string{"foo", "bar"}) || v4.CoordinatorKey != "" {
+		t.Errorf("v4: got unexpected key %q, keys %v", v4.CoordinatorKey, v4.CoordinatorKeys)
+	}
+
+	if _, err := BuildFindCoordinator(2, "foo"); err == nil {
+		t.Error("expected an error for an invalid key type")
+	}
+}
+
+func TestCoordinatorFor(t *testing.T) {
+	v3 := NewPtrFindCoordinatorResponse()
+	v3.SetVersion(3)
+	v3.NodeID, v3.Host, v3.Port = 2, "two", 9092
+	c, ok := v3.CoordinatorFor("foo")
+	if !ok || c.Key != "foo" || c.NodeID != 2 || c.Host != "two" || c.Port != 9092 {
+		t.Errorf("v3: got unexpected %v (ok? %v)", c, ok)
+	}
+
+	v4 := NewPtrFindCoordinatorResponse()
+	v4.SetVersion(4)
+	v4.Coordinators = []FindCoordinatorResponseCoordinator{
+		{Key: "foo", NodeID: 1, Host: "one", Port: 9092},
+		{Key: "bar", NodeID: -1, ErrorCode: 15},
+	}
+	if c, ok := v4.CoordinatorFor("foo"); !ok || c.NodeID != 1 || c.Host != "one" {
+		t.Errorf("v4 foo: got unexpected %v (ok? %v)", c, ok)
+	}
+	if c, ok := v4.CoordinatorFor("bar"); !ok || c.ErrorCode != 15 {
+		t.Errorf("v4 bar: got unexpected %v (ok? %v)", c, ok)
+	}
+	if _, ok := v4.CoordinatorFor("baz"); ok {
+		t.Error("v4 baz: got ok, expected missing")
+	}
+}
+
+func TestValidCoordinatorKeyType(t *testing.T) {
+	for _, test := range []struct {
+		keyType int8
+		valid   bool
+	}{
+		{CoordinatorKeyGroup, true},
+		{CoordinatorKeyTransaction, true},
+		{2, false},
+		{-1, false},
+	} {
+		if got := ValidCoordinatorKeyType(test.keyType); got != test.valid {
+			t.Errorf("key type %d: got valid %v != exp %v", test.keyType, got, test.valid)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package kmsg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DescribeRequest returns a one line description of r suitable for debug
+// logging, such as "Fetch v12 flexible Topics=2 ForgottenTopics=0". The
+// description contains the name of the request, its version, whether the
+// version is flexible, and the length of every top level array field.
+func DescribeRequest(r Request) string {
+	return describe(r.Key(), r.GetVersion(), r.IsFlexible(), false, r)
+}
+
+// DescribeResponse returns a one line description of r suitable for debug
+// logging, such as "Metadata v9 flexible Brokers=3 Topics=1". This is the
+// same as DescribeRequest, but additionally includes a top level ErrorCode if
+// the response has one and it is non-zero.
+func DescribeResponse(r Response) string {
+	return describe(r.Key(), r.GetVersion(), r.IsFlexible(), true, r)
+}
+
+func describe(key, version int16, flexible, errCode bool, m interface{}) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s v%d", NameForKey(key), version)
+	if flexible {
+		sb.WriteString(" flexible")
+	}
+
+	rv := reflect.ValueOf(m)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return sb.String()
+	}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		fv := rv.Field(i)
+		switch {
+		case errCode && f.Name == "ErrorCode" && fv.Kind() == reflect.Int16:
+			if code := fv.Int(); code != 0 {
+				fmt.Fprintf(&sb, " ErrorCode=%d", code)
+			}
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8:
+			fmt.Fprintf(&sb, " %s=%d", f.Name, fv.Len())
+		}
+	}
+	return sb.String()
+}
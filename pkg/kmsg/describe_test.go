@@ -0,0 +1,36 @@
+package kmsg
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	fetch := NewPtrFetchRequest()
+	fetch.Version = 11
+	fetch.Topics = make([]FetchRequestTopic, 2)
+
+	fetch12 := NewPtrFetchRequest()
+	fetch12.Version = 12
+	fetch12.Topics = make([]FetchRequestTopic, 2)
+
+	meta := NewPtrMetadataResponse()
+	meta.Version = 9
+	meta.Brokers = make([]MetadataResponseBroker, 3)
+
+	joinResp := NewPtrJoinGroupResponse()
+	joinResp.Version = 2
+	joinResp.ErrorCode = 25
+
+	for i, test := range []struct {
+		got string
+		exp string
+	}{
+		{DescribeRequest(fetch), "Fetch v11 Topics=2 ForgottenTopics=0"},
+		{DescribeRequest(fetch12), "Fetch v12 flexible Topics=2 ForgottenTopics=0"},
+		{DescribeRequest(NewPtrApiVersionsRequest()), "ApiVersions v0"},
+		{DescribeResponse(meta), "Metadata v9 flexible Brokers=3 Topics=0"},
+		{DescribeResponse(joinResp), "JoinGroup v2 ErrorCode=25 Members=0"},
+	} {
+		if test.got != test.exp {
+			t.Errorf("#%d: got %q != exp %q", i, test.got, test.exp)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package kmsg
+
+// protocolNames contains the names of requests as used in the Kafka protocol
+// documentation, for the requests whose names in this package differ.
+var protocolNames = map[Key]string{
+	LeaderAndISR:                 "LeaderAndIsr",
+	SASLHandshake:                "SaslHandshake",
+	InitProducerID:               "InitProducerId",
+	DescribeACLs:                 "DescribeAcls",
+	CreateACLs:                   "CreateAcls",
+	DeleteACLs:                   "DeleteAcls",
+	SASLAuthenticate:             "SaslAuthenticate",
+	AlterPartitionAssignments:    "AlterPartitionReassignments",
+	DescribeUserSCRAMCredentials: "DescribeUserScramCredentials",
+	AlterUserSCRAMCredentials:    "AlterUserScramCredentials",
+	AllocateProducerIDs:          "AllocateProducerIds",
+}
+
+// DocURL returns a link to the documentation of the request and response for
+// the given key in the Kafka protocol guide, or an empty string if the key is
+// unknown. See KIPFor for links to the design documents of a request.
+func DocURL(key int16) string {
+	if RequestForKey(key) == nil {
+		return ""
+	}
+	name, ok := protocolNames[Key(key)]
+	if !ok {
+		name = NameForKey(key)
+	}
+	return "https://kafka.apache.org/protocol.html#The_Messages_" + name
+}
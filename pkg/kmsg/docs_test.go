@@ -0,0 +1,31 @@
+package kmsg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocs(t *testing.T) {
+	for _, test := range []struct {
+		key Key
+		kip string
+		url string
+	}{
+		{Fetch, "KIP-320", "#The_Messages_Fetch"},
+		{JoinGroup, "KIP-345", "#The_Messages_JoinGroup"},
+		{OffsetDelete, "KIP-496", "#The_Messages_OffsetDelete"},
+		{AlterPartitionAssignments, "KIP-455", "#The_Messages_AlterPartitionReassignments"},
+		{InitProducerID, "", "#The_Messages_InitProducerId"},
+	} {
+		kips := KIPFor(int16(test.key))
+		if test.kip != "" && !strings.Contains(strings.Join(kips, ","), test.kip) {
+			t.Errorf("%s: got KIPs %v, expected to contain %s", test.key.Name(), kips, test.kip)
+		}
+		if url := DocURL(int16(test.key)); !strings.HasPrefix(url, "https://kafka.apache.org/protocol.html") || !strings.HasSuffix(url, test.url) {
+			t.Errorf("%s: got url %s, expected suffix %s", test.key.Name(), url, test.url)
+		}
+	}
+	if kips, url := KIPFor(-1), DocURL(-1); kips != nil || url != "" {
+		t.Errorf("unknown key: got %v %q, expected nothing", kips, url)
+	}
+}
@@ -0,0 +1,154 @@
+package kmsg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ResponseErrorWithMessage returns the top level ErrorCode and ErrorMessage of
+// r, if r has them. The code and message can be converted to an error that
+// preserves the broker's explanation with kerr.ErrorForCodeWithMessage.
+//
+// If r has a top level ErrorCode but no ErrorMessage, or if the ErrorMessage
+// is null, the returned message is empty. If r has no top level ErrorCode,
+// this returns 0 and an empty message. Only top level fields are inspected;
+// per-topic or per-partition errors are not returned.
+func ResponseErrorWithMessage(r Response) (code int16, msg string) {
+	rv := reflect.ValueOf(r)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return 0, ""
+	}
+	fc := rv.FieldByName("ErrorCode")
+	if !fc.IsValid() || fc.Kind() != reflect.Int16 {
+		return 0, ""
+	}
+	code = int16(fc.Int())
+	if fm := rv.FieldByName("ErrorMessage"); fm.IsValid() && fm.Kind() == reflect.Ptr && !fm.IsNil() {
+		if s, ok := fm.Interface().(*string); ok {
+			msg = *s
+		}
+	}
+	return code, msg
+}
+
+// SetResponseErrorWithMessage sets the top level ErrorCode and ErrorMessage of
+// r, returning false if r has no top level ErrorCode. This is the inverse of
+// ResponseErrorWithMessage, and can be used in a mock broker to return an
+// error with an explanation. If r has no ErrorMessage field, only the code is
+// set. An empty msg sets a null ErrorMessage.
+//
+// The ErrorMessage is only serialized at the versions that support it, which
+// for some responses is later than the ErrorCode.
+func SetResponseErrorWithMessage(r Response, code int16, msg string) bool {
+	rv := reflect.ValueOf(r)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	rv = rv.Elem()
+	fc := rv.FieldByName("ErrorCode")
+	if !fc.IsValid() || fc.Kind() != reflect.Int16 {
+		return false
+	}
+	fc.SetInt(int64(code))
+	if fm := rv.FieldByName("ErrorMessage"); fm.IsValid() && fm.Type() == reflect.TypeOf((*string)(nil)) {
+		if msg == "" {
+			fm.Set(reflect.Zero(fm.Type()))
+		} else {
+			fm.Set(reflect.ValueOf(&msg))
+		}
+	}
+	return true
+}
+
+// PartitionFailure is a partition in a response with a non-zero error code.
+type PartitionFailure struct {
+	Topic        string
+	Partition    int32
+	ErrorCode    int16
+	ErrorMessage string
+}
+
+// Error implements error, allowing a PartitionFailure to be returned as the
+// error for a partition. The underlying code can be retrieved with errors.As
+// and converted to a kerr error with kerr.ErrorForCode.
+func (f PartitionFailure) Error() string {
+	if f.ErrorMessage != "" {
+		return fmt.Sprintf("topic %s partition %d: error code %d: %s", f.Topic, f.Partition, f.ErrorCode, f.ErrorMessage)
+	}
+	return fmt.Sprintf("topic %s partition %d: error code %d", f.Topic, f.Partition, f.ErrorCode)
+}
+
+// GroupFailure is a group in a response with a non-zero error code. As with
+// PartitionFailure, a GroupFailure implements error, and the code can be
+// converted to a kerr error with kerr.ErrorForCode.
+type GroupFailure struct {
+	Group     string
+	ErrorCode int16
+}
+
+func (f GroupFailure) Error() string {
+	return fmt.Sprintf("group %s: error code %d", f.Group, f.ErrorCode)
+}
+
+// PartitionFailures returns every partition in r that has a non-zero error
+// code, in the order the partitions appear in r. A partition is any struct
+// with an int32 Partition field and an int16 ErrorCode field, and its topic is
+// the Topic field of the closest enclosing struct that has one. The
+// ErrorMessage is the partition's ErrorMessage, if it has one and it is not
+// null. Top level errors are not returned; see ResponseErrorWithMessage.
+//
+// Each error code can be converted to an error with kerr.ErrorForCode, and
+// kerr.IsRetriable can then be used to retry only the retriable subset of the
+// failed partitions.
+func PartitionFailures(r Response) []PartitionFailure {
+	var failures []PartitionFailure
+	partitionFailures(reflect.ValueOf(r), "", &failures)
+	return failures
+}
+
+func partitionFailures(v reflect.Value, topic string, failures *[]PartitionFailure) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			partitionFailures(v.Elem(), topic, failures)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			partitionFailures(v.Index(i), topic, failures)
+		}
+	case reflect.Struct:
+		if f := v.FieldByName("Topic"); f.IsValid() {
+			switch {
+			case f.Kind() == reflect.String:
+				topic = f.String()
+			case f.Kind() == reflect.Ptr && f.Type().Elem().Kind() == reflect.String && !f.IsNil():
+				topic = f.Elem().String()
+			}
+		}
+		fp, fc := v.FieldByName("Partition"), v.FieldByName("ErrorCode")
+		if fp.IsValid() && fp.Kind() == reflect.Int32 && fc.IsValid() && fc.Kind() == reflect.Int16 {
+			if code := int16(fc.Int()); code != 0 {
+				failure := PartitionFailure{
+					Topic:     topic,
+					Partition: int32(fp.Int()),
+					ErrorCode: code,
+				}
+				if fm := v.FieldByName("ErrorMessage"); fm.IsValid() && fm.Kind() == reflect.Ptr && !fm.IsNil() {
+					if s, ok := fm.Interface().(*string); ok {
+						failure.ErrorMessage = *s
+					}
+				}
+				*failures = append(*failures, failure)
+			}
+		}
+		for i := 0; i < v.NumField(); i++ {
+			partitionFailures(v.Field(i), topic, failures)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package kmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResponseErrorWithMessage(t *testing.T) {
+	msg := "partition reassignment is in progress"
+	withMsg := NewPtrAlterPartitionAssignmentsResponse()
+	withMsg.ErrorCode = 60
+	withMsg.ErrorMessage = &msg
+
+	noMsg := NewPtrApiVersionsResponse()
+	noMsg.ErrorCode = 35
+
+	nullMsg := NewPtrDescribeClusterResponse()
+	nullMsg.ErrorCode = 41
+
+	for _, test := range []struct {
+		r       Response
+		expCode int16
+		expMsg  string
+	}{
+		{withMsg, 60, msg},
+		{noMsg, 35, ""},
+		{nullMsg, 41, ""},
+		{NewPtrProduceResponse(), 0, ""},
+	} {
+		code, msg := ResponseErrorWithMessage(test.r)
+		if code != test.expCode || msg != test.expMsg {
+			t.Errorf("%s: got (%d, %q) != exp (%d, %q)", NameForKey(test.r.Key()), code, msg, test.expCode, test.expMsg)
+		}
+	}
+}
+
+func TestSetResponseErrorWithMessage(t *testing.T) {
+	resp := NewPtrDescribeClusterResponse()
+	if !SetResponseErrorWithMessage(resp, 41, "no controller") {
+		t.Fatal("unable to set error on describe cluster response")
+	}
+	var decoded DescribeClusterResponse
+	if err := decoded.ReadFrom(resp.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to decode: %v", err)
+	}
+	if code, msg := ResponseErrorWithMessage(&decoded); code != 41 || msg != "no controller" {
+		t.Errorf("got (%d, %q) != exp (41, %q)", code, msg, "no controller")
+	}
+
+	if !SetResponseErrorWithMessage(resp, 0, "") || resp.ErrorCode != 0 || resp.ErrorMessage != nil {
+		t.Errorf("got (%d, %v) after clearing, expected no error", resp.ErrorCode, resp.ErrorMessage)
+	}
+
+	noMsg := NewPtrApiVersionsResponse()
+	if !SetResponseErrorWithMessage(noMsg, 35, "ignored") || noMsg.ErrorCode != 35 {
+		t.Errorf("api versions: got code %d != exp 35", noMsg.ErrorCode)
+	}
+
+	// CreateTopics errors are per topic, with no top level ErrorCode.
+	if SetResponseErrorWithMessage(NewPtrCreateTopicsResponse(), 41, "no controller") {
+		t.Error("unexpectedly set a top level error on a create topics response")
+	}
+}
+
+func TestPartitionFailures(t *testing.T) {
+	msg := "not leader"
+	resp := NewPtrProduceResponse()
+	resp.Topics = []ProduceResponseTopic{
+		{Topic: "foo", Partitions: []ProduceResponseTopicPartition{
+			{Partition: 0},
+			{Partition: 1, ErrorCode: 6, ErrorMessage: &msg}, // retriable
+			{Partition: 2, ErrorCode: 10},                    // not retriable
+		}},
+		{Topic: "bar", Partitions: []ProduceResponseTopicPartition{
+			{Partition: 0, ErrorCode: 7}, // retriable
+		}},
+	}
+	exp := []PartitionFailure{
+		{Topic: "foo", Partition: 1, ErrorCode: 6, ErrorMessage: msg},
+		{Topic: "foo", Partition: 2, ErrorCode: 10},
+		{Topic: "bar", Partition: 0, ErrorCode: 7},
+	}
+	if got := PartitionFailures(resp); !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+	if got := PartitionFailures(NewPtrProduceResponse()); len(got) != 0 {
+		t.Errorf("got %v for an empty response, expected none", got)
+	}
+}
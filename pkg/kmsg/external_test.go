@@ -0,0 +1,87 @@
+package kmsg_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// These tests use only the exported API to ensure that errors returned from
+// decoding can be checked by code outside of this module.
+
+func TestExternalReadRecordsVarintOverflow(t *testing.T) {
+	rec := kmsg.NewRecord()
+	rec.Value = []byte("v")
+	rec.Length = int32(len(rec.AppendTo(nil)) - 1)
+	in := append(rec.AppendTo(nil), 0xff, 0xff, 0xff, 0xff, 0x7f)
+
+	rs, err := kmsg.ReadRecords(2, in)
+	if !errors.Is(err, kmsg.ErrVarintOverflow) || len(rs) != 1 {
+		t.Errorf("got %d records and err %v, expected 1 record and varint overflow", len(rs), err)
+	}
+}
+
+func TestExternalReadRecordsShort(t *testing.T) {
+	rec := kmsg.NewRecord()
+	rec.Value = []byte("value")
+	rec.Length = int32(len(rec.AppendTo(nil)) - 1)
+	full := rec.AppendTo(nil)
+	in := full[:len(full)-2]
+
+	rs, err := kmsg.ReadRecords(1, in)
+	if !errors.Is(err, kmsg.ErrNotEnoughData) || len(rs) != 0 {
+		t.Fatalf("got %d records and err %v, expected 0 records and not enough data", len(rs), err)
+	}
+	var short *kmsg.ErrShort
+	if !errors.As(err, &short) {
+		t.Fatalf("got err %T, expected *kmsg.ErrShort", err)
+	}
+	if short.Need != len(full) || short.Have != len(in) {
+		t.Errorf("got need %d have %d != exp need %d have %d", short.Need, short.Have, len(full), len(in))
+	}
+}
+
+func TestExternalReadBatchLeaderEpochShort(t *testing.T) {
+	epoch, err := kmsg.ReadBatchLeaderEpoch(make([]byte, 10))
+	var short *kmsg.ErrShort
+	if !errors.As(err, &short) || epoch != -1 {
+		t.Fatalf("got epoch %d and err %v, expected -1 and *kmsg.ErrShort", epoch, err)
+	}
+	if short.Need != 17 || short.Have != 10 {
+		t.Errorf("got need %d have %d != exp need 17 have 10", short.Need, short.Have)
+	}
+	if !errors.Is(err, kmsg.ErrNotEnoughData) {
+		t.Errorf("got err %v, expected errors.Is kmsg.ErrNotEnoughData", err)
+	}
+}
+
+func TestExternalReadResponseHeaderShort(t *testing.T) {
+	for _, test := range []struct {
+		frame    []byte
+		flexible bool
+		need     int
+	}{
+		{[]byte{0, 0}, false, 4},
+		{[]byte{0, 0, 0, 7}, true, 5},              // missing the tag count
+		{[]byte{0, 0, 0, 7, 1, 0, 3, 1}, true, 10}, // one tag of 3 bytes, 1 present
+	} {
+		_, _, err := kmsg.ReadResponseHeader(test.frame, test.flexible)
+		var short *kmsg.ErrShort
+		if !errors.As(err, &short) {
+			t.Errorf("%x: got err %v, expected *kmsg.ErrShort", test.frame, err)
+			continue
+		}
+		if short.Need != test.need || short.Have != len(test.frame) {
+			t.Errorf("%x: got need %d have %d != exp need %d have %d", test.frame, short.Need, short.Have, test.need, len(test.frame))
+		}
+	}
+
+	// A short frame is distinguishable from a correlation ID mismatch.
+	if err := kmsg.MatchCorrelation([]byte{0, 0}, 7, false); !errors.Is(err, kmsg.ErrNotEnoughData) {
+		t.Errorf("got err %v, expected errors.Is kmsg.ErrNotEnoughData for a short frame", err)
+	}
+	if err := kmsg.MatchCorrelation([]byte{0, 0, 0, 8}, 7, false); err == nil || errors.Is(err, kmsg.ErrNotEnoughData) {
+		t.Errorf("got err %v, expected a mismatch that is not kmsg.ErrNotEnoughData", err)
+	}
+}
@@ -0,0 +1,274 @@
+package kmsg
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// EachRecord calls fn for every record in every partition in the response,
+// along with the record's absolute offset and timestamp, decompressing as
+// necessary. Partitions may contain v2 record batches as well as legacy v0 and
+// v1 message sets, which are upconverted to records as in ReadRecordSet. For
+// records from a v2 batch, batch is the record's batch; for records from
+// legacy messages, batch is nil. For batches using LogAppendTime, the
+// timestamp is the broker append time, and v0 messages use a timestamp of -1.
+//
+// Control batches (see RecordBatch.IsControl) and batches belonging to aborted
+// transactions are not filtered; it is up to fn to skip these if necessary.
+// The batch and record passed to fn are reused between calls and must not be
+// retained. As with ReadRecordBatches, a truncated final batch or message in
+// a partition is discarded.
+//
+// This returns the first error encountered reading batches, decompressing, or
+// reading records, wrapped with the topic and partition that failed.
+// Compression codecs other than gzip require a registered decompressor; see
+// RegisterDecompressor.
+func (v *FetchResponse) EachRecord(fn func(topic string, partition int32, batch *RecordBatch, rec *Record, offset, timestamp int64)) error {
+	var (
+		batches []RecordBatch
+		records []Record
+	)
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			for in := p.RecordBatches; len(in) > 17; {
+				length := 12 + int(int32(binary.BigEndian.Uint32(in[8:])))
+				if length < 17 {
+					return fmt.Errorf("topic %s partition %d: invalid record set entry length %d", t.Topic, p.Partition, length-12)
+				}
+				if len(in) < length {
+					break // truncated final batch or message
+				}
+				raw := in[:length]
+				in = in[length:]
+
+				if raw[16] != 2 {
+					first, rs, err := ReadRecordSet(raw)
+					if err != nil {
+						return fmt.Errorf("topic %s partition %d: %w", t.Topic, p.Partition, err)
+					}
+					for k := range rs {
+						r := &rs[k]
+						fn(t.Topic, p.Partition, nil, r, first+int64(r.OffsetDelta), r.TimestampDelta64)
+					}
+					continue
+				}
+
+				var err error
+				batches, err = ReadRecordBatchesInto(batches, raw)
+				if err != nil {
+					return fmt.Errorf("topic %s partition %d: %w", t.Topic, p.Partition, err)
+				}
+				if len(batches) == 0 {
+					return fmt.Errorf("topic %s partition %d: record batch with length %d is too short", t.Topic, p.Partition, length-12)
+				}
+				b := &batches[0]
+				decompressed, err := Decompress(b.Codec(), b.Records)
+				if err != nil {
+					return fmt.Errorf("topic %s partition %d: unable to decompress batch at offset %d: %w", t.Topic, p.Partition, b.FirstOffset, err)
+				}
+				records, err = ReadRecordsInto(records, int(b.NumRecords), decompressed)
+				if err != nil {
+					return fmt.Errorf("topic %s partition %d: unable to read records in batch at offset %d: %w", t.Topic, p.Partition, b.FirstOffset, err)
+				}
+				for k := range records {
+					r := &records[k]
+					fn(t.Topic, p.Partition, b, r, b.FirstOffset+int64(r.OffsetDelta), b.recordTimestamp(r))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// VerifyCRCs verifies the CRC of every complete record batch (or legacy v0 or
+// v1 message) in every partition of the response without decoding the
+// batches, returning the first mismatch wrapped with the topic and partition
+// that failed. A mismatch wraps ErrEncodedCRCMismatch. As with
+// ReadRecordBatches, a truncated final batch in a partition is ignored.
+//
+// This can be used to detect a corrupt response before processing any of it.
+func (v *FetchResponse) VerifyCRCs() error {
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			if err := verifyRecordSetCRCs(p.RecordBatches); err != nil {
+				return fmt.Errorf("topic %s partition %d: %w", t.Topic, p.Partition, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Watermarks returns the high watermark, last stable offset, and log start
+// offset of the given partition in the response, or false if the partition is
+// not in the response. Topics are matched by name, meaning this does not find
+// any partition in v13+ responses, which identify topics only by ID.
+//
+// The last stable offset is -1 for responses prior to v4, and the log start
+// offset is -1 for responses prior to v5, which do not contain the fields.
+func (v *FetchResponse) Watermarks(topic string, partition int32) (high, lastStable, logStart int64, ok bool) {
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		if t.Topic != topic {
+			continue
+		}
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			if p.Partition == partition {
+				return p.HighWatermark, p.LastStableOffset, p.LogStartOffset, true
+			}
+		}
+	}
+	return -1, -1, -1, false
+}
+
+// HasMore returns whether the broker has more records for the given partition
+// beyond what is in the response, which is the case if the response was
+// limited by the fetch's max bytes. This compares the offset following the
+// last complete batch in the partition to the partition's high watermark. The
+// last offset is read from the batch headers without decompressing any
+// batch.
+//
+// A partition with no complete batch returns false: brokers always return at
+// least one batch if the fetch offset is below the high watermark (KIP-74),
+// so an empty partition is caught up. This returns an error if the partition
+// is not in the response, if the partition has an error code (which can be
+// converted to an error with kerr.ErrorForCode), or if the record set is
+// malformed. As with Watermarks, topics are matched by name.
+func (v *FetchResponse) HasMore(topic string, partition int32) (bool, error) {
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		if t.Topic != topic {
+			continue
+		}
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			if p.Partition != partition {
+				continue
+			}
+			if p.ErrorCode != 0 {
+				return false, fmt.Errorf("topic %s partition %d: error code %d", topic, partition, p.ErrorCode)
+			}
+			last, ok, err := lastRecordSetOffset(p.RecordBatches)
+			if err != nil {
+				return false, fmt.Errorf("topic %s partition %d: %w", topic, partition, err)
+			}
+			return ok && last+1 < p.HighWatermark, nil
+		}
+	}
+	return false, fmt.Errorf("topic %s partition %d: not in the response", topic, partition)
+}
+
+// IsEmpty returns whether no partition in the response has any record bytes,
+// meaning the consumer is caught up on every partition and can back off
+// before fetching again. This only inspects the length of each partition's
+// record set and does not decode anything; a partition containing only a
+// truncated partial batch is not considered empty.
+func (v *FetchResponse) IsEmpty() bool {
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		for j := range t.Partitions {
+			if len(t.Partitions[j].RecordBatches) > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SessionError returns the top level error code of the response, which in
+// v7+ responses is how the broker reports a problem with an incremental fetch
+// session (KIP-227), and whether the error requires the client to reset its
+// session. The code can be converted to an error with kerr.ErrorForCode.
+//
+// A reset is required for FETCH_SESSION_ID_NOT_FOUND,
+// INVALID_FETCH_SESSION_EPOCH, and FETCH_SESSION_TOPIC_ID_ERROR: the client
+// must discard its session and issue a full fetch with session epoch 0 (or -1
+// to fetch without a session). On success, the response's SessionID field is
+// the ID of the session to use in the next incremental fetch, or 0 if the
+// broker did not create a session.
+func (v *FetchResponse) SessionError() (code int16, reset bool) {
+	switch v.ErrorCode {
+	case 70, // FETCH_SESSION_ID_NOT_FOUND
+		71,  // INVALID_FETCH_SESSION_EPOCH
+		106: // FETCH_SESSION_TOPIC_ID_ERROR
+		return v.ErrorCode, true
+	}
+	return v.ErrorCode, false
+}
+
+// LeaderEpochCache tracks the latest leader epoch seen for each partition, as
+// is necessary for a consumer to detect log truncation (KIP-320). A consumer
+// observes the leader epoch of each partition from metadata responses and
+// record batches, and sends the latest epoch as the CurrentLeaderEpoch in
+// fetch and list offsets requests. If the broker has a different epoch, it
+// replies with FENCED_LEADER_EPOCH or UNKNOWN_LEADER_EPOCH rather than serving
+// possibly truncated data.
+//
+// The zero value is ready to use. A LeaderEpochCache is not safe for
+// concurrent use.
+type LeaderEpochCache struct {
+	epochs map[string]map[int32]int32
+}
+
+// Observe records epoch for the given partition if it is higher than the
+// epoch currently known for the partition. Epochs only move forward, so an
+// out of date observation (such as from stale metadata) is ignored. Negative
+// epochs, which mean the epoch is unknown, are ignored.
+func (c *LeaderEpochCache) Observe(topic string, partition, epoch int32) {
+	if epoch < 0 {
+		return
+	}
+	if c.epochs == nil {
+		c.epochs = make(map[string]map[int32]int32)
+	}
+	ps := c.epochs[topic]
+	if ps == nil {
+		ps = make(map[int32]int32)
+		c.epochs[topic] = ps
+	}
+	if current, ok := ps[partition]; !ok || epoch > current {
+		ps[partition] = epoch
+	}
+}
+
+// Current returns the latest epoch observed for the given partition, or -1 if
+// no epoch has been observed. -1 is the CurrentLeaderEpoch that skips epoch
+// validation in the broker.
+func (c *LeaderEpochCache) Current(topic string, partition int32) int32 {
+	if epoch, ok := c.epochs[topic][partition]; ok {
+		return epoch
+	}
+	return -1
+}
+
+// StampFetch sets the CurrentLeaderEpoch of every partition in req to the
+// partition's current epoch. Partitions without an observed epoch are set to
+// -1. CurrentLeaderEpoch is only serialized in v9+ fetch requests. As with
+// FetchResponse.Watermarks, topics are matched by name, meaning this does not
+// stamp v13+ requests that identify topics only by ID.
+func (c *LeaderEpochCache) StampFetch(req *FetchRequest) {
+	for i := range req.Topics {
+		t := &req.Topics[i]
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			p.CurrentLeaderEpoch = c.Current(t.Topic, p.Partition)
+		}
+	}
+}
+
+// StampListOffsets sets the CurrentLeaderEpoch of every partition in req to
+// the partition's current epoch, as in StampFetch. CurrentLeaderEpoch is only
+// serialized in v4+ list offsets requests.
+func (c *LeaderEpochCache) StampListOffsets(req *ListOffsetsRequest) {
+	for i := range req.Topics {
+		t := &req.Topics[i]
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			p.CurrentLeaderEpoch = c.Current(t.Topic, p.Partition)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package kmsg
+
+import (
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kmsg/internal/kbin"
+)
+
+// testFetchResponse returns a fetch response with the given record sets for
+// partitions 0, 1, ... of topic "foo".
+func testFetchResponse(recordSets ...[]byte) *FetchResponse {
+	resp := NewPtrFetchResponse()
+	t := NewFetchResponseTopic()
+	t.Topic = "foo"
+	for i, rs := range recordSets {
+		p := NewFetchResponseTopicPartition()
+		p.Partition = int32(i)
+		p.RecordBatches = rs
+		t.Partitions = append(t.Partitions, p)
+	}
+	resp.Topics = append(resp.Topics, t)
+	return resp
+}
+
+func TestFetchResponseEachRecord(t *testing.T) {
+	resp := testFetchResponse(
+		append(testBatch(0, testRecords(2)), testBatchCodec(2, CodecGzip, testRecords(1))...),
+		testBatchCodec(10, CodecGzip, testRecords(3)),
+		append(testV1Message(30, 0, 300, nil, []byte("l0")), testV1Message(31, 0, 310, nil, []byte("l1"))...),
+	)
+
+	type seen struct {
+		partition int32
+		offset    int64
+		timestamp int64
+		value     string
+		legacy    bool
+	}
+	var got []seen
+	if err := resp.EachRecord(func(topic string, partition int32, b *RecordBatch, r *Record, offset, timestamp int64) {
+		if topic != "foo" {
+			t.Errorf("got unexpected topic %s", topic)
+		}
+		got = append(got, seen{partition, offset, timestamp, string(r.Value), b == nil})
+	}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	exp := []seen{
+		{0, 0, 0, "v0", false},
+		{0, 1, 0, "v1", false},
+		{0, 2, 0, "v0", false},
+		{1, 10, 0, "v0", false},
+		{1, 11, 0, "v1", false},
+		{1, 12, 0, "v2", false},
+		{2, 30, 300, "l0", true},
+		{2, 31, 310, "l1", true},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+
+	// A codec without a registered decompressor errors.
+	snappy := testBatchCodec(0, CodecSnappy, testRecords(1))
+	if err := testFetchResponse(nil, snappy).EachRecord(func(string, int32, *RecordBatch, *Record, int64, int64) {}); err == nil || !strings.Contains(err.Error(), "decompress") {
+		t.Errorf("got err %v, expected decompress error for unregistered snappy codec", err)
+	}
+}
+
+func TestFetchResponseVerifyCRCs(t *testing.T) {
+	v1 := MessageV1{Magic: 1, Timestamp: 1, Value: []byte("v")}
+	v1.MessageSize = int32(len(v1.AppendTo(nil)) - 12)
+	legacy := v1.AppendTo(nil)
+	kbin.AppendInt32(legacy[12:12], int32(crc32.ChecksumIEEE(legacy[16:])))
+
+	good := func() []byte {
+		return append(testBatch(0, testRecords(2)), testBatchCodec(2, CodecGzip, testRecords(1))...)
+	}
+	resp := testFetchResponse(
+		good(),
+		append(legacy, testBatch(1, testRecords(1))[:20]...), // legacy with a truncated tail
+		good(),
+	)
+	if err := resp.VerifyCRCs(); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	corrupt := resp.Topics[0].Partitions[2].RecordBatches
+	corrupt[len(corrupt)-1] ^= 0xff
+	err := resp.VerifyCRCs()
+	if !errors.Is(err, ErrEncodedCRCMismatch) || !strings.Contains(err.Error(), "partition 2") {
+		t.Errorf("got err %v, expected a crc mismatch for partition 2", err)
+	}
+
+	legacy[len(legacy)-1] ^= 0xff
+	resp = testFetchResponse(legacy)
+	if err := resp.VerifyCRCs(); !errors.Is(err, ErrEncodedCRCMismatch) {
+		t.Errorf("got err %v, expected a crc mismatch for the legacy message", err)
+	}
+
+	// A legacy message with a size too small to hold its own header must
+	// error rather than panic.
+	short := append(kbin.AppendInt32(make([]byte, 8), 0), 0, 0, 0, 0, 1)
+	resp = testFetchResponse(append(short, make([]byte, 30)...))
+	if err := resp.VerifyCRCs(); err == nil || !strings.Contains(err.Error(), "too short") {
+		t.Errorf("got err %v, expected a too short error", err)
+	}
+}
+
+func TestFetchResponseWatermarks(t *testing.T) {
+	resp := testFetchResponse(nil, nil)
+	resp.SetVersion(11)
+	p := &resp.Topics[0].Partitions[1]
+	p.HighWatermark, p.LastStableOffset, p.LogStartOffset = 100, 90, 10
+
+	if high, stable, start, ok := resp.Watermarks("foo", 1); !ok || high != 100 || stable != 90 || start != 10 {
+		t.Errorf("v11: got (%d, %d, %d, %v) != exp (100, 90, 10, true)", high, stable, start, ok)
+	}
+	if _, _, _, ok := resp.Watermarks("foo", 2); ok {
+		t.Error("unexpectedly found missing partition")
+	}
+	if _, _, _, ok := resp.Watermarks("bar", 1); ok {
+		t.Error("unexpectedly found missing topic")
+	}
+
+	// v3 contains neither the last stable offset nor the log start offset.
+	resp.SetVersion(3)
+	v3 := NewPtrFetchResponse()
+	v3.SetVersion(3)
+	if err := v3.ReadFrom(resp.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read v3: %v", err)
+	}
+	if high, stable, start, ok := v3.Watermarks("foo", 1); !ok || high != 100 || stable != -1 || start != -1 {
+		t.Errorf("v3: got (%d, %d, %d, %v) != exp (100, -1, -1, true)", high, stable, start, ok)
+	}
+}
+
+func TestFetchResponseHasMore(t *testing.T) {
+	resp := testFetchResponse(
+		append(testBatch(10, testRecords(3)), testBatch(13, testRecords(2))...), // last offset 14
+		testBatch(20, testRecords(2)),                                           // last offset 21
+		nil,
+		append(testV1Message(30, 0, 0, nil, []byte("v")), testV1Message(31, 0, 0, nil, []byte("v"))...),
+	)
+	resp.Topics[0].Partitions[0].HighWatermark = 15
+	resp.Topics[0].Partitions[1].HighWatermark = 100
+	resp.Topics[0].Partitions[2].HighWatermark = 5
+	resp.Topics[0].Partitions[3].HighWatermark = 40
+
+	for _, test := range []struct {
+		partition int32
+		exp       bool
+	}{
+		{0, false}, // caught up
+		{1, true},
+		{2, false}, // no records
+		{3, true},  // legacy messages
+	} {
+		if more, err := resp.HasMore("foo", test.partition); err != nil || more != test.exp {
+			t.Errorf("partition %d: got more %v and err %v, expected %v", test.partition, more, err, test.exp)
+		}
+	}
+
+	if _, err := resp.HasMore("foo", 9); err == nil {
+		t.Error("expected error for a missing partition")
+	}
+	resp.Topics[0].Partitions[1].ErrorCode = 1
+	if _, err := resp.HasMore("foo", 1); err == nil {
+		t.Error("expected error for a partition with an error code")
+	}
+}
+
+func TestFetchResponseIsEmpty(t *testing.T) {
+	if !NewPtrFetchResponse().IsEmpty() {
+		t.Error("response with no topics is not empty")
+	}
+	if !testFetchResponse(nil, nil).IsEmpty() {
+		t.Error("response with no records is not empty")
+	}
+	if testFetchResponse(nil, testBatch(0, testRecords(1))).IsEmpty() {
+		t.Error("response with records is empty")
+	}
+}
+
+func TestLeaderEpochCache(t *testing.T) {
+	var c LeaderEpochCache
+	if got := c.Current("foo", 0); got != -1 {
+		t.Errorf("empty cache: got %d != exp -1", got)
+	}
+
+	c.Observe("foo", 0, 3)
+	c.Observe("foo", 0, 5)
+	c.Observe("foo", 0, 4) // stale, ignored
+	c.Observe("foo", 1, -1)
+	if got := c.Current("foo", 0); got != 5 {
+		t.Errorf("foo[0]: got %d != exp 5", got)
+	}
+	if got := c.Current("foo", 1); got != -1 {
+		t.Errorf("foo[1]: got %d != exp -1", got)
+	}
+
+	req := NewPtrFetchRequest()
+	rt := NewFetchRequestTopic()
+	rt.Topic = "foo"
+	for _, p := range []int32{0, 1} {
+		rp := NewFetchRequestTopicPartition()
+		rp.Partition = p
+		rp.CurrentLeaderEpoch = 9
+		rt.Partitions = append(rt.Partitions, rp)
+	}
+	req.Topics = append(req.Topics, rt)
+	c.StampFetch(req)
+	if got0, got1 := req.Topics[0].Partitions[0].CurrentLeaderEpoch, req.Topics[0].Partitions[1].CurrentLeaderEpoch; got0 != 5 || got1 != -1 {
+		t.Errorf("fetch: got epochs (%d, %d) != exp (5, -1)", got0, got1)
+	}
+
+	list := NewPtrListOffsetsRequest()
+	lt := NewListOffsetsRequestTopic()
+	lt.Topic = "foo"
+	lt.Partitions = append(lt.Partitions, NewListOffsetsRequestTopicPartition())
+	list.Topics = append(list.Topics, lt)
+	c.StampListOffsets(list)
+	if got := list.Topics[0].Partitions[0].CurrentLeaderEpoch; got != 5 {
+		t.Errorf("list offsets: got epoch %d != exp 5", got)
+	}
+}
+
+func TestFetchResponseSessionError(t *testing.T) {
+	resp := NewPtrFetchResponse()
+	resp.SetVersion(7)
+	resp.SessionID = 12
+	if code, reset := resp.SessionError(); code != 0 || reset {
+		t.Errorf("healthy: got (%d, %v) != exp (0, false)", code, reset)
+	}
+
+	resp.ErrorCode = 71 // INVALID_FETCH_SESSION_EPOCH
+	resp.SessionID = 0
+	var decoded FetchResponse
+	decoded.SetVersion(7)
+	if err := decoded.ReadFrom(resp.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to decode: %v", err)
+	}
+	if code, reset := decoded.SessionError(); code != 71 || !reset {
+		t.Errorf("invalid epoch: got (%d, %v) != exp (71, true)", code, reset)
+	}
+
+	resp.ErrorCode = -1 // UNKNOWN_SERVER_ERROR
+	if code, reset := resp.SessionError(); code != -1 || reset {
+		t.Errorf("unknown: got (%d, %v) != exp (-1, false)", code, reset)
+	}
+}
@@ -400,6 +400,9 @@ type RecordBatch struct {
 	// Bit 6 indicates if the batch includes a control message (1 is yes).
 	// Control messages are used to enable transactions and are generated from
 	// the broker. Clients should not return control batches to applications.
+	//
+	// Bit 7 indicates whether FirstTimestamp is the delete horizon of the
+	// batch rather than the timestamp of the first record (KIP-534).
 	Attributes int16
 
 	// LastOffsetDelta is the offset of the last message in a batch. This is used
@@ -408,6 +411,11 @@ type RecordBatch struct {
 
 	// FirstTimestamp is the timestamp (in milliseconds) of the first record
 	// in a batch.
+	//
+	// If the batch has the delete horizon attribute set (KIP-534), this is
+	// instead the delete horizon: the time after which the broker removes
+	// tombstones and transaction markers in the batch during compaction.
+	// Record timestamp deltas are still relative to this field.
 	FirstTimestamp int64
 
 	// MaxTimestamp is the timestamp (in milliseconds) of the last record
@@ -2804,6 +2812,15 @@ func (v *ProduceRequest) RequestWith(ctx context.Context, r Requestor) (*Produce
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *ProduceRequest) ReadResponseFrom(body []byte) (*ProduceResponse, error) {
+	resp := &ProduceResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *ProduceRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -3352,14 +3369,14 @@ func (v *ProduceResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *ProduceResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *ProduceResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *ProduceResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ProduceResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -3522,10 +3539,16 @@ func (v *ProduceResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -3767,6 +3790,15 @@ func (v *FetchRequest) RequestWith(ctx context.Context, r Requestor) (*FetchResp
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *FetchRequest) ReadResponseFrom(body []byte) (*FetchResponse, error) {
+	resp := &FetchResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *FetchRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -4775,14 +4807,14 @@ func (v *FetchResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *FetchResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *FetchResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
+func (v *FetchResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -4793,14 +4825,23 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if version >= 7 {
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if version >= 7 {
 		v := b.Int32()
 		s.SessionID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "SessionID")
+		}
 	}
 	{
 		v := s.Topics
@@ -5009,6 +5050,9 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -5176,6 +5220,15 @@ func (v *ListOffsetsRequest) RequestWith(ctx context.Context, r Requestor) (*Lis
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *ListOffsetsRequest) ReadResponseFrom(body []byte) (*ListOffsetsResponse, error) {
+	resp := &ListOffsetsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *ListOffsetsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -5617,14 +5670,14 @@ func (v *ListOffsetsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *ListOffsetsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *ListOffsetsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *ListOffsetsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ListOffsetsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -5635,6 +5688,9 @@ func (v *ListOffsetsResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 2 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -5749,6 +5805,9 @@ func (v *ListOffsetsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -5856,6 +5915,15 @@ func (v *MetadataRequest) RequestWith(ctx context.Context, r Requestor) (*Metada
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *MetadataRequest) ReadResponseFrom(body []byte) (*MetadataResponse, error) {
+	resp := &MetadataResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *MetadataRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -6437,14 +6505,14 @@ func (v *MetadataResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *MetadataResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *MetadataResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
+func (v *MetadataResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -6455,6 +6523,9 @@ func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 3 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Brokers
@@ -6524,6 +6595,9 @@ func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Brokers = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Brokers")
+		}
 	}
 	if version >= 2 {
 		var v *string
@@ -6541,10 +6615,16 @@ func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ClusterID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ClusterID")
+		}
 	}
 	if version >= 1 {
 		v := b.Int32()
 		s.ControllerID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ControllerID")
+		}
 	}
 	{
 		v := s.Topics
@@ -6735,10 +6815,16 @@ func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if version >= 8 && version <= 10 {
 		v := b.Int32()
 		s.AuthorizedOperations = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "AuthorizedOperations")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -6944,6 +7030,15 @@ func (v *LeaderAndISRRequest) RequestWith(ctx context.Context, r Requestor) (*Le
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *LeaderAndISRRequest) ReadResponseFrom(body []byte) (*LeaderAndISRResponse, error) {
+	resp := &LeaderAndISRResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *LeaderAndISRRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -7866,14 +7961,14 @@ func (v *LeaderAndISRResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *LeaderAndISRResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *LeaderAndISRResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *LeaderAndISRResponse) readFrom(src []byte, unsafe bool) error {
+func (v *LeaderAndISRResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -7884,6 +7979,9 @@ func (v *LeaderAndISRResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if version >= 0 && version <= 4 {
 		v := s.Partitions
@@ -7936,6 +8034,9 @@ func (v *LeaderAndISRResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Partitions = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Partitions")
+		}
 	}
 	if version >= 5 {
 		v := s.Topics
@@ -8019,6 +8120,9 @@ func (v *LeaderAndISRResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -8152,6 +8256,15 @@ func (v *StopReplicaRequest) RequestWith(ctx context.Context, r Requestor) (*Sto
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *StopReplicaRequest) ReadResponseFrom(body []byte) (*StopReplicaResponse, error) {
+	resp := &StopReplicaResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *StopReplicaRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -8517,14 +8630,14 @@ func (v *StopReplicaResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *StopReplicaResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *StopReplicaResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *StopReplicaResponse) readFrom(src []byte, unsafe bool) error {
+func (v *StopReplicaResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -8535,6 +8648,9 @@ func (v *StopReplicaResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Partitions
@@ -8587,6 +8703,9 @@ func (v *StopReplicaResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Partitions = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Partitions")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -8784,6 +8903,15 @@ func (v *UpdateMetadataRequest) RequestWith(ctx context.Context, r Requestor) (*
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *UpdateMetadataRequest) ReadResponseFrom(body []byte) (*UpdateMetadataResponse, error) {
+	resp := &UpdateMetadataResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *UpdateMetadataRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -9624,14 +9752,14 @@ func (v *UpdateMetadataResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *UpdateMetadataResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *UpdateMetadataResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *UpdateMetadataResponse) readFrom(src []byte, unsafe bool) error {
+func (v *UpdateMetadataResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -9642,6 +9770,9 @@ func (v *UpdateMetadataResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -9714,6 +9845,15 @@ func (v *ControlledShutdownRequest) RequestWith(ctx context.Context, r Requestor
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *ControlledShutdownRequest) ReadResponseFrom(body []byte) (*ControlledShutdownResponse, error) {
+	resp := &ControlledShutdownResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *ControlledShutdownRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -9874,14 +10014,14 @@ func (v *ControlledShutdownResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *ControlledShutdownResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *ControlledShutdownResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *ControlledShutdownResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ControlledShutdownResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -9892,6 +10032,9 @@ func (v *ControlledShutdownResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.PartitionsRemaining
@@ -9940,6 +10083,9 @@ func (v *ControlledShutdownResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.PartitionsRemaining = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "PartitionsRemaining")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -10105,6 +10251,15 @@ func (v *OffsetCommitRequest) RequestWith(ctx context.Context, r Requestor) (*Of
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *OffsetCommitRequest) ReadResponseFrom(body []byte) (*OffsetCommitResponse, error) {
+	resp := &OffsetCommitResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *OffsetCommitRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -10592,14 +10747,14 @@ func (v *OffsetCommitResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *OffsetCommitResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *OffsetCommitResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *OffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetCommitResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -10610,6 +10765,9 @@ func (v *OffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 3 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -10689,6 +10847,9 @@ func (v *OffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -10836,6 +10997,15 @@ func (v *OffsetFetchRequest) RequestWith(ctx context.Context, r Requestor) (*Off
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *OffsetFetchRequest) ReadResponseFrom(body []byte) (*OffsetFetchResponse, error) {
+	resp := &OffsetFetchResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *OffsetFetchRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -11580,14 +11750,14 @@ func (v *OffsetFetchResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *OffsetFetchResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *OffsetFetchResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *OffsetFetchResponse) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetFetchResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -11598,6 +11768,9 @@ func (v *OffsetFetchResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 3 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if version >= 0 && version <= 7 {
 		v := s.Topics
@@ -11702,10 +11875,16 @@ func (v *OffsetFetchResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if version >= 2 && version <= 7 {
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if version >= 8 {
 		v := s.Groups
@@ -11858,6 +12037,9 @@ func (v *OffsetFetchResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Groups = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Groups")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -11931,6 +12113,15 @@ func (v *FindCoordinatorRequest) RequestWith(ctx context.Context, r Requestor) (
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *FindCoordinatorRequest) ReadResponseFrom(body []byte) (*FindCoordinatorResponse, error) {
+	resp := &FindCoordinatorResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *FindCoordinatorRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -12261,14 +12452,14 @@ func (v *FindCoordinatorResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *FindCoordinatorResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *FindCoordinatorResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *FindCoordinatorResponse) readFrom(src []byte, unsafe bool) error {
+func (v *FindCoordinatorResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -12279,10 +12470,16 @@ func (v *FindCoordinatorResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if version >= 0 && version <= 3 {
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if version >= 1 && version <= 3 {
 		var v *string
@@ -12300,10 +12497,16 @@ func (v *FindCoordinatorResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	if version >= 0 && version <= 3 {
 		v := b.Int32()
 		s.NodeID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "NodeID")
+		}
 	}
 	if version >= 0 && version <= 3 {
 		var v string
@@ -12321,10 +12524,16 @@ func (v *FindCoordinatorResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.Host = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Host")
+		}
 	}
 	if version >= 0 && version <= 3 {
 		v := b.Int32()
 		s.Port = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Port")
+		}
 	}
 	if version >= 4 {
 		v := s.Coordinators
@@ -12415,6 +12624,9 @@ func (v *FindCoordinatorResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Coordinators = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Coordinators")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -12571,6 +12783,15 @@ func (v *JoinGroupRequest) RequestWith(ctx context.Context, r Requestor) (*JoinG
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *JoinGroupRequest) ReadResponseFrom(body []byte) (*JoinGroupResponse, error) {
+	resp := &JoinGroupResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *JoinGroupRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -13089,14 +13310,14 @@ func (v *JoinGroupResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *JoinGroupResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *JoinGroupResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool) error {
+func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -13107,14 +13328,23 @@ func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 2 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := b.Int32()
 		s.Generation = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Generation")
+		}
 	}
 	if version >= 7 {
 		var v *string
@@ -13132,6 +13362,9 @@ func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ProtocolType = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ProtocolType")
+		}
 	}
 	{
 		var v *string
@@ -13167,6 +13400,9 @@ func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.Protocol = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Protocol")
+		}
 	}
 	{
 		var v string
@@ -13184,10 +13420,16 @@ func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.LeaderID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "LeaderID")
+		}
 	}
 	if version >= 9 {
 		v := b.Bool()
 		s.SkipAssignment = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "SkipAssignment")
+		}
 	}
 	{
 		var v string
@@ -13205,6 +13447,9 @@ func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.MemberID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "MemberID")
+		}
 	}
 	{
 		v := s.Members
@@ -13275,6 +13520,9 @@ func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Members = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Members")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -13347,6 +13595,15 @@ func (v *HeartbeatRequest) RequestWith(ctx context.Context, r Requestor) (*Heart
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *HeartbeatRequest) ReadResponseFrom(body []byte) (*HeartbeatResponse, error) {
+	resp := &HeartbeatResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *HeartbeatRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -13553,14 +13810,14 @@ func (v *HeartbeatResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *HeartbeatResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *HeartbeatResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *HeartbeatResponse) readFrom(src []byte, unsafe bool) error {
+func (v *HeartbeatResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -13571,10 +13828,16 @@ func (v *HeartbeatResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -13672,6 +13935,15 @@ func (v *LeaveGroupRequest) RequestWith(ctx context.Context, r Requestor) (*Leav
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *LeaveGroupRequest) ReadResponseFrom(body []byte) (*LeaveGroupResponse, error) {
+	resp := &LeaveGroupResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *LeaveGroupRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -14023,14 +14295,14 @@ func (v *LeaveGroupResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *LeaveGroupResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *LeaveGroupResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *LeaveGroupResponse) readFrom(src []byte, unsafe bool) error {
+func (v *LeaveGroupResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -14041,10 +14313,16 @@ func (v *LeaveGroupResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if version >= 3 {
 		v := s.Members
@@ -14110,6 +14388,9 @@ func (v *LeaveGroupResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Members = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Members")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -14219,6 +14500,15 @@ func (v *SyncGroupRequest) RequestWith(ctx context.Context, r Requestor) (*SyncG
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *SyncGroupRequest) ReadResponseFrom(body []byte) (*SyncGroupResponse, error) {
+	resp := &SyncGroupResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *SyncGroupRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -14595,14 +14885,14 @@ func (v *SyncGroupResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *SyncGroupResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *SyncGroupResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *SyncGroupResponse) readFrom(src []byte, unsafe bool) error {
+func (v *SyncGroupResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -14613,10 +14903,16 @@ func (v *SyncGroupResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if version >= 5 {
 		var v *string
@@ -14634,6 +14930,9 @@ func (v *SyncGroupResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ProtocolType = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ProtocolType")
+		}
 	}
 	if version >= 5 {
 		var v *string
@@ -14651,6 +14950,9 @@ func (v *SyncGroupResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.Protocol = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Protocol")
+		}
 	}
 	{
 		var v []byte
@@ -14660,6 +14962,9 @@ func (v *SyncGroupResponse) readFrom(src []byte, unsafe bool) error {
 			v = b.Bytes()
 		}
 		s.MemberAssignment = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "MemberAssignment")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -14726,6 +15031,15 @@ func (v *DescribeGroupsRequest) RequestWith(ctx context.Context, r Requestor) (*
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeGroupsRequest) ReadResponseFrom(body []byte) (*DescribeGroupsResponse, error) {
+	resp := &DescribeGroupsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeGroupsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -15104,14 +15418,14 @@ func (v *DescribeGroupsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeGroupsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeGroupsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeGroupsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeGroupsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -15122,6 +15436,9 @@ func (v *DescribeGroupsResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Groups
@@ -15338,6 +15655,9 @@ func (v *DescribeGroupsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Groups = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Groups")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -15403,6 +15723,15 @@ func (v *ListGroupsRequest) RequestWith(ctx context.Context, r Requestor) (*List
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *ListGroupsRequest) ReadResponseFrom(body []byte) (*ListGroupsResponse, error) {
+	resp := &ListGroupsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *ListGroupsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -15633,14 +15962,14 @@ func (v *ListGroupsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *ListGroupsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *ListGroupsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *ListGroupsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ListGroupsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -15651,10 +15980,16 @@ func (v *ListGroupsResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Groups
@@ -15733,6 +16068,9 @@ func (v *ListGroupsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Groups = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Groups")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -15798,6 +16136,15 @@ func (v *SASLHandshakeRequest) RequestWith(ctx context.Context, r Requestor) (*S
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *SASLHandshakeRequest) ReadResponseFrom(body []byte) (*SASLHandshakeResponse, error) {
+	resp := &SASLHandshakeResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *SASLHandshakeRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -15898,14 +16245,14 @@ func (v *SASLHandshakeResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *SASLHandshakeResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *SASLHandshakeResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *SASLHandshakeResponse) readFrom(src []byte, unsafe bool) error {
+func (v *SASLHandshakeResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -15914,6 +16261,9 @@ func (v *SASLHandshakeResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.SupportedMechanisms
@@ -15938,6 +16288,9 @@ func (v *SASLHandshakeResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.SupportedMechanisms = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "SupportedMechanisms")
+		}
 	}
 	return b.Complete()
 }
@@ -16018,6 +16371,15 @@ func (v *ApiVersionsRequest) RequestWith(ctx context.Context, r Requestor) (*Api
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *ApiVersionsRequest) ReadResponseFrom(body []byte) (*ApiVersionsResponse, error) {
+	resp := &ApiVersionsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *ApiVersionsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -16420,14 +16782,14 @@ func (v *ApiVersionsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *ApiVersionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *ApiVersionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -16438,6 +16800,9 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.ApiKeys
@@ -16477,10 +16842,16 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.ApiKeys = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ApiKeys")
+		}
 	}
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if isFlexible {
 		for i := b.Uvarint(); i > 0; i-- {
@@ -16542,6 +16913,9 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 				if err := b.Complete(); err != nil {
 					return err
 				}
+				if read != nil && b.Ok() {
+					*read = append(*read, "SupportedFeatures")
+				}
 			case 1:
 				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
 				v := b.Int64()
@@ -16549,6 +16923,9 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 				if err := b.Complete(); err != nil {
 					return err
 				}
+				if read != nil && b.Ok() {
+					*read = append(*read, "FinalizedFeaturesEpoch")
+				}
 			case 2:
 				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
 				v := s.FinalizedFeatures
@@ -16604,6 +16981,9 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 				if err := b.Complete(); err != nil {
 					return err
 				}
+				if read != nil && b.Ok() {
+					*read = append(*read, "FinalizedFeatures")
+				}
 			case 3:
 				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
 				v := b.Bool()
@@ -16611,6 +16991,9 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 				if err := b.Complete(); err != nil {
 					return err
 				}
+				if read != nil && b.Ok() {
+					*read = append(*read, "ZkMigrationReady")
+				}
 			}
 		}
 	}
@@ -16779,6 +17162,15 @@ func (v *CreateTopicsRequest) RequestWith(ctx context.Context, r Requestor) (*Cr
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *CreateTopicsRequest) ReadResponseFrom(body []byte) (*CreateTopicsResponse, error) {
+	resp := &CreateTopicsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *CreateTopicsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -17382,14 +17774,14 @@ func (v *CreateTopicsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *CreateTopicsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *CreateTopicsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *CreateTopicsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *CreateTopicsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -17400,6 +17792,9 @@ func (v *CreateTopicsResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 2 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -17565,6 +17960,9 @@ func (v *CreateTopicsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -17661,6 +18059,15 @@ func (v *DeleteTopicsRequest) RequestWith(ctx context.Context, r Requestor) (*De
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DeleteTopicsRequest) ReadResponseFrom(body []byte) (*DeleteTopicsResponse, error) {
+	resp := &DeleteTopicsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DeleteTopicsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -18005,14 +18412,14 @@ func (v *DeleteTopicsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DeleteTopicsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DeleteTopicsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DeleteTopicsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteTopicsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -18023,6 +18430,9 @@ func (v *DeleteTopicsResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 1 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -18110,6 +18520,9 @@ func (v *DeleteTopicsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -18242,6 +18655,15 @@ func (v *DeleteRecordsRequest) RequestWith(ctx context.Context, r Requestor) (*D
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DeleteRecordsRequest) ReadResponseFrom(body []byte) (*DeleteRecordsResponse, error) {
+	resp := &DeleteRecordsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DeleteRecordsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -18600,14 +19022,14 @@ func (v *DeleteRecordsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DeleteRecordsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DeleteRecordsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DeleteRecordsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteRecordsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -18618,6 +19040,9 @@ func (v *DeleteRecordsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -18701,6 +19126,9 @@ func (v *DeleteRecordsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -18787,6 +19215,15 @@ func (v *InitProducerIDRequest) RequestWith(ctx context.Context, r Requestor) (*
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *InitProducerIDRequest) ReadResponseFrom(body []byte) (*InitProducerIDResponse, error) {
+	resp := &InitProducerIDResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *InitProducerIDRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -18981,14 +19418,14 @@ func (v *InitProducerIDResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *InitProducerIDResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *InitProducerIDResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *InitProducerIDResponse) readFrom(src []byte, unsafe bool) error {
+func (v *InitProducerIDResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -18999,18 +19436,30 @@ func (v *InitProducerIDResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := b.Int64()
 		s.ProducerID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ProducerID")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ProducerEpoch = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ProducerEpoch")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -19141,6 +19590,15 @@ func (v *OffsetForLeaderEpochRequest) RequestWith(ctx context.Context, r Request
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *OffsetForLeaderEpochRequest) ReadResponseFrom(body []byte) (*OffsetForLeaderEpochResponse, error) {
+	resp := &OffsetForLeaderEpochResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *OffsetForLeaderEpochRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -19538,14 +19996,14 @@ func (v *OffsetForLeaderEpochResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *OffsetForLeaderEpochResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *OffsetForLeaderEpochResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *OffsetForLeaderEpochResponse) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetForLeaderEpochResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -19556,6 +20014,9 @@ func (v *OffsetForLeaderEpochResponse) readFrom(src []byte, unsafe bool) error {
 	if version >= 2 {
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -19643,6 +20104,9 @@ func (v *OffsetForLeaderEpochResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -19743,6 +20207,15 @@ func (v *AddPartitionsToTxnRequest) RequestWith(ctx context.Context, r Requestor
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *AddPartitionsToTxnRequest) ReadResponseFrom(body []byte) (*AddPartitionsToTxnResponse, error) {
+	resp := &AddPartitionsToTxnResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *AddPartitionsToTxnRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -20120,14 +20593,14 @@ func (v *AddPartitionsToTxnResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *AddPartitionsToTxnResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *AddPartitionsToTxnResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *AddPartitionsToTxnResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AddPartitionsToTxnResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -20138,6 +20611,9 @@ func (v *AddPartitionsToTxnResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -20217,6 +20693,9 @@ func (v *AddPartitionsToTxnResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -20296,6 +20775,15 @@ func (v *AddOffsetsToTxnRequest) RequestWith(ctx context.Context, r Requestor) (
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *AddOffsetsToTxnRequest) ReadResponseFrom(body []byte) (*AddOffsetsToTxnResponse, error) {
+	resp := &AddOffsetsToTxnResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *AddOffsetsToTxnRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -20481,14 +20969,14 @@ func (v *AddOffsetsToTxnResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *AddOffsetsToTxnResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *AddOffsetsToTxnResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *AddOffsetsToTxnResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AddOffsetsToTxnResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -20499,10 +20987,16 @@ func (v *AddOffsetsToTxnResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -20576,6 +21070,15 @@ func (v *EndTxnRequest) RequestWith(ctx context.Context, r Requestor) (*EndTxnRe
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *EndTxnRequest) ReadResponseFrom(body []byte) (*EndTxnResponse, error) {
+	resp := &EndTxnResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *EndTxnRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -20749,14 +21252,14 @@ func (v *EndTxnResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *EndTxnResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *EndTxnResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *EndTxnResponse) readFrom(src []byte, unsafe bool) error {
+func (v *EndTxnResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -20767,10 +21270,16 @@ func (v *EndTxnResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -20892,6 +21401,15 @@ func (v *WriteTxnMarkersRequest) RequestWith(ctx context.Context, r Requestor) (
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *WriteTxnMarkersRequest) ReadResponseFrom(body []byte) (*WriteTxnMarkersResponse, error) {
+	resp := &WriteTxnMarkersResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *WriteTxnMarkersRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -21309,14 +21827,14 @@ func (v *WriteTxnMarkersResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *WriteTxnMarkersResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *WriteTxnMarkersResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *WriteTxnMarkersResponse) readFrom(src []byte, unsafe bool) error {
+func (v *WriteTxnMarkersResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -21433,6 +21951,9 @@ func (v *WriteTxnMarkersResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Markers = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Markers")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -21586,6 +22107,15 @@ func (v *TxnOffsetCommitRequest) RequestWith(ctx context.Context, r Requestor) (
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *TxnOffsetCommitRequest) ReadResponseFrom(body []byte) (*TxnOffsetCommitResponse, error) {
+	resp := &TxnOffsetCommitResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *TxnOffsetCommitRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -22107,14 +22637,14 @@ func (v *TxnOffsetCommitResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *TxnOffsetCommitResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *TxnOffsetCommitResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *TxnOffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
+func (v *TxnOffsetCommitResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -22125,6 +22655,9 @@ func (v *TxnOffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -22204,6 +22737,9 @@ func (v *TxnOffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -22295,6 +22831,15 @@ func (v *DescribeACLsRequest) RequestWith(ctx context.Context, r Requestor) (*De
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeACLsRequest) ReadResponseFrom(body []byte) (*DescribeACLsResponse, error) {
+	resp := &DescribeACLsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeACLsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -22704,14 +23249,14 @@ func (v *DescribeACLsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeACLsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeACLsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeACLsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeACLsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -22722,10 +23267,16 @@ func (v *DescribeACLsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v *string
@@ -22743,6 +23294,9 @@ func (v *DescribeACLsResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	{
 		v := s.Resources
@@ -22884,6 +23438,9 @@ func (v *DescribeACLsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Resources = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Resources")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -22998,6 +23555,15 @@ func (v *CreateACLsRequest) RequestWith(ctx context.Context, r Requestor) (*Crea
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *CreateACLsRequest) ReadResponseFrom(body []byte) (*CreateACLsResponse, error) {
+	resp := &CreateACLsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *CreateACLsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -23331,14 +23897,14 @@ func (v *CreateACLsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *CreateACLsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *CreateACLsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *CreateACLsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *CreateACLsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -23349,6 +23915,9 @@ func (v *CreateACLsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Results
@@ -23397,6 +23966,9 @@ func (v *CreateACLsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Results = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Results")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -23493,6 +24065,15 @@ func (v *DeleteACLsRequest) RequestWith(ctx context.Context, r Requestor) (*Dele
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DeleteACLsRequest) ReadResponseFrom(body []byte) (*DeleteACLsResponse, error) {
+	resp := &DeleteACLsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DeleteACLsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -23948,14 +24529,14 @@ func (v *DeleteACLsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DeleteACLsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DeleteACLsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DeleteACLsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteACLsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -23966,6 +24547,9 @@ func (v *DeleteACLsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Results
@@ -24149,6 +24733,9 @@ func (v *DeleteACLsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Results = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Results")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -24253,6 +24840,15 @@ func (v *DescribeConfigsRequest) RequestWith(ctx context.Context, r Requestor) (
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeConfigsRequest) ReadResponseFrom(body []byte) (*DescribeConfigsResponse, error) {
+	resp := &DescribeConfigsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeConfigsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -24787,14 +25383,14 @@ func (v *DescribeConfigsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeConfigsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeConfigsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeConfigsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeConfigsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -24805,6 +25401,9 @@ func (v *DescribeConfigsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Resources
@@ -25057,6 +25656,9 @@ func (v *DescribeConfigsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Resources = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Resources")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -25195,6 +25797,15 @@ func (v *AlterConfigsRequest) RequestWith(ctx context.Context, r Requestor) (*Al
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *AlterConfigsRequest) ReadResponseFrom(body []byte) (*AlterConfigsResponse, error) {
+	resp := &AlterConfigsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *AlterConfigsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -25565,14 +26176,14 @@ func (v *AlterConfigsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *AlterConfigsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *AlterConfigsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *AlterConfigsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterConfigsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -25583,6 +26194,9 @@ func (v *AlterConfigsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Resources
@@ -25657,6 +26271,9 @@ func (v *AlterConfigsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Resources = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Resources")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -25769,6 +26386,15 @@ func (v *AlterReplicaLogDirsRequest) RequestWith(ctx context.Context, r Requesto
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *AlterReplicaLogDirsRequest) ReadResponseFrom(body []byte) (*AlterReplicaLogDirsResponse, error) {
+	resp := &AlterReplicaLogDirsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *AlterReplicaLogDirsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -26153,14 +26779,14 @@ func (v *AlterReplicaLogDirsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *AlterReplicaLogDirsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *AlterReplicaLogDirsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *AlterReplicaLogDirsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterReplicaLogDirsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -26171,6 +26797,9 @@ func (v *AlterReplicaLogDirsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -26250,6 +26879,9 @@ func (v *AlterReplicaLogDirsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -26336,6 +26968,15 @@ func (v *DescribeLogDirsRequest) RequestWith(ctx context.Context, r Requestor) (
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeLogDirsRequest) ReadResponseFrom(body []byte) (*DescribeLogDirsResponse, error) {
+	resp := &DescribeLogDirsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeLogDirsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -26753,14 +27394,14 @@ func (v *DescribeLogDirsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeLogDirsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeLogDirsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeLogDirsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeLogDirsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -26771,10 +27412,16 @@ func (v *DescribeLogDirsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if version >= 3 {
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Dirs
@@ -26918,6 +27565,9 @@ func (v *DescribeLogDirsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Dirs = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Dirs")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -26983,6 +27633,15 @@ func (v *SASLAuthenticateRequest) RequestWith(ctx context.Context, r Requestor)
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *SASLAuthenticateRequest) ReadResponseFrom(body []byte) (*SASLAuthenticateResponse, error) {
+	resp := &SASLAuthenticateResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *SASLAuthenticateRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -27125,14 +27784,14 @@ func (v *SASLAuthenticateResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *SASLAuthenticateResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *SASLAuthenticateResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *SASLAuthenticateResponse) readFrom(src []byte, unsafe bool) error {
+func (v *SASLAuthenticateResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -27143,6 +27802,9 @@ func (v *SASLAuthenticateResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v *string
@@ -27160,6 +27822,9 @@ func (v *SASLAuthenticateResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	{
 		var v []byte
@@ -27169,10 +27834,16 @@ func (v *SASLAuthenticateResponse) readFrom(src []byte, unsafe bool) error {
 			v = b.Bytes()
 		}
 		s.SASLAuthBytes = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "SASLAuthBytes")
+		}
 	}
 	if version >= 1 {
 		v := b.Int64()
 		s.SessionLifetimeMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "SessionLifetimeMillis")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -27307,6 +27978,15 @@ func (v *CreatePartitionsRequest) RequestWith(ctx context.Context, r Requestor)
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *CreatePartitionsRequest) ReadResponseFrom(body []byte) (*CreatePartitionsResponse, error) {
+	resp := &CreatePartitionsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *CreatePartitionsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -27669,14 +28349,14 @@ func (v *CreatePartitionsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *CreatePartitionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *CreatePartitionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *CreatePartitionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *CreatePartitionsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -27687,6 +28367,9 @@ func (v *CreatePartitionsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -27752,6 +28435,9 @@ func (v *CreatePartitionsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -27861,6 +28547,15 @@ func (v *CreateDelegationTokenRequest) RequestWith(ctx context.Context, r Reques
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *CreateDelegationTokenRequest) ReadResponseFrom(body []byte) (*CreateDelegationTokenResponse, error) {
+	resp := &CreateDelegationTokenResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *CreateDelegationTokenRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -28219,14 +28914,14 @@ func (v *CreateDelegationTokenResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *CreateDelegationTokenResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *CreateDelegationTokenResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
+func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -28237,6 +28932,9 @@ func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v string
@@ -28254,6 +28952,9 @@ func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 			}
 		}
 		s.PrincipalType = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "PrincipalType")
+		}
 	}
 	{
 		var v string
@@ -28271,6 +28972,9 @@ func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 			}
 		}
 		s.PrincipalName = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "PrincipalName")
+		}
 	}
 	if version >= 3 {
 		var v string
@@ -28288,6 +28992,9 @@ func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 			}
 		}
 		s.TokenRequesterPrincipalType = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "TokenRequesterPrincipalType")
+		}
 	}
 	if version >= 3 {
 		var v string
@@ -28305,18 +29012,30 @@ func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 			}
 		}
 		s.TokenRequesterPrincipalName = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "TokenRequesterPrincipalName")
+		}
 	}
 	{
 		v := b.Int64()
 		s.IssueTimestamp = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "IssueTimestamp")
+		}
 	}
 	{
 		v := b.Int64()
 		s.ExpiryTimestamp = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ExpiryTimestamp")
+		}
 	}
 	{
 		v := b.Int64()
 		s.MaxTimestamp = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "MaxTimestamp")
+		}
 	}
 	{
 		var v string
@@ -28334,6 +29053,9 @@ func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 			}
 		}
 		s.TokenID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "TokenID")
+		}
 	}
 	{
 		var v []byte
@@ -28343,10 +29065,16 @@ func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 			v = b.Bytes()
 		}
 		s.HMAC = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "HMAC")
+		}
 	}
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -28413,6 +29141,15 @@ func (v *RenewDelegationTokenRequest) RequestWith(ctx context.Context, r Request
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *RenewDelegationTokenRequest) ReadResponseFrom(body []byte) (*RenewDelegationTokenResponse, error) {
+	resp := &RenewDelegationTokenResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *RenewDelegationTokenRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -28560,14 +29297,14 @@ func (v *RenewDelegationTokenResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *RenewDelegationTokenResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *RenewDelegationTokenResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *RenewDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
+func (v *RenewDelegationTokenResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -28578,14 +29315,23 @@ func (v *RenewDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := b.Int64()
 		s.ExpiryTimestamp = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ExpiryTimestamp")
+		}
 	}
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -28654,6 +29400,15 @@ func (v *ExpireDelegationTokenRequest) RequestWith(ctx context.Context, r Reques
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *ExpireDelegationTokenRequest) ReadResponseFrom(body []byte) (*ExpireDelegationTokenResponse, error) {
+	resp := &ExpireDelegationTokenResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *ExpireDelegationTokenRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -28800,14 +29555,14 @@ func (v *ExpireDelegationTokenResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *ExpireDelegationTokenResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *ExpireDelegationTokenResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *ExpireDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ExpireDelegationTokenResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -28818,14 +29573,23 @@ func (v *ExpireDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := b.Int64()
 		s.ExpiryTimestamp = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ExpiryTimestamp")
+		}
 	}
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -28914,6 +29678,15 @@ func (v *DescribeDelegationTokenRequest) RequestWith(ctx context.Context, r Requ
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeDelegationTokenRequest) ReadResponseFrom(body []byte) (*DescribeDelegationTokenResponse, error) {
+	resp := &DescribeDelegationTokenResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeDelegationTokenRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -29303,14 +30076,14 @@ func (v *DescribeDelegationTokenResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeDelegationTokenResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeDelegationTokenResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeDelegationTokenResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -29321,6 +30094,9 @@ func (v *DescribeDelegationTokenResponse) readFrom(src []byte, unsafe bool) erro
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.TokenDetails
@@ -29515,10 +30291,16 @@ func (v *DescribeDelegationTokenResponse) readFrom(src []byte, unsafe bool) erro
 		}
 		v = a
 		s.TokenDetails = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "TokenDetails")
+		}
 	}
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -29582,6 +30364,15 @@ func (v *DeleteGroupsRequest) RequestWith(ctx context.Context, r Requestor) (*De
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DeleteGroupsRequest) ReadResponseFrom(body []byte) (*DeleteGroupsResponse, error) {
+	resp := &DeleteGroupsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DeleteGroupsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -29799,14 +30590,14 @@ func (v *DeleteGroupsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DeleteGroupsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DeleteGroupsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DeleteGroupsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteGroupsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -29817,6 +30608,9 @@ func (v *DeleteGroupsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Groups
@@ -29865,6 +30659,9 @@ func (v *DeleteGroupsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Groups = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Groups")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -29971,6 +30768,15 @@ func (v *ElectLeadersRequest) RequestWith(ctx context.Context, r Requestor) (*El
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *ElectLeadersRequest) ReadResponseFrom(body []byte) (*ElectLeadersResponse, error) {
+	resp := &ElectLeadersResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *ElectLeadersRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -30321,14 +31127,14 @@ func (v *ElectLeadersResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *ElectLeadersResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *ElectLeadersResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *ElectLeadersResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ElectLeadersResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -30339,10 +31145,16 @@ func (v *ElectLeadersResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	if version >= 1 {
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Topics
@@ -30439,6 +31251,9 @@ func (v *ElectLeadersResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -30585,6 +31400,15 @@ func (v *IncrementalAlterConfigsRequest) RequestWith(ctx context.Context, r Requ
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *IncrementalAlterConfigsRequest) ReadResponseFrom(body []byte) (*IncrementalAlterConfigsResponse, error) {
+	resp := &IncrementalAlterConfigsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *IncrementalAlterConfigsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -30976,14 +31800,14 @@ func (v *IncrementalAlterConfigsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *IncrementalAlterConfigsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *IncrementalAlterConfigsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *IncrementalAlterConfigsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *IncrementalAlterConfigsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -30994,6 +31818,9 @@ func (v *IncrementalAlterConfigsResponse) readFrom(src []byte, unsafe bool) erro
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Resources
@@ -31068,6 +31895,9 @@ func (v *IncrementalAlterConfigsResponse) readFrom(src []byte, unsafe bool) erro
 		}
 		v = a
 		s.Resources = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Resources")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -31193,6 +32023,15 @@ func (v *AlterPartitionAssignmentsRequest) RequestWith(ctx context.Context, r Re
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *AlterPartitionAssignmentsRequest) ReadResponseFrom(body []byte) (*AlterPartitionAssignmentsResponse, error) {
+	resp := &AlterPartitionAssignmentsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *AlterPartitionAssignmentsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -31599,14 +32438,14 @@ func (v *AlterPartitionAssignmentsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *AlterPartitionAssignmentsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *AlterPartitionAssignmentsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *AlterPartitionAssignmentsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterPartitionAssignmentsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -31617,10 +32456,16 @@ func (v *AlterPartitionAssignmentsResponse) readFrom(src []byte, unsafe bool) er
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v *string
@@ -31638,6 +32483,9 @@ func (v *AlterPartitionAssignmentsResponse) readFrom(src []byte, unsafe bool) er
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	{
 		v := s.Topics
@@ -31734,6 +32582,9 @@ func (v *AlterPartitionAssignmentsResponse) readFrom(src []byte, unsafe bool) er
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -31835,6 +32686,15 @@ func (v *ListPartitionReassignmentsRequest) RequestWith(ctx context.Context, r R
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *ListPartitionReassignmentsRequest) ReadResponseFrom(body []byte) (*ListPartitionReassignmentsResponse, error) {
+	resp := &ListPartitionReassignmentsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *ListPartitionReassignmentsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -32212,14 +33072,14 @@ func (v *ListPartitionReassignmentsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *ListPartitionReassignmentsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *ListPartitionReassignmentsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *ListPartitionReassignmentsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ListPartitionReassignmentsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -32230,10 +33090,16 @@ func (v *ListPartitionReassignmentsResponse) readFrom(src []byte, unsafe bool) e
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v *string
@@ -32251,6 +33117,9 @@ func (v *ListPartitionReassignmentsResponse) readFrom(src []byte, unsafe bool) e
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	{
 		v := s.Topics
@@ -32395,6 +33264,9 @@ func (v *ListPartitionReassignmentsResponse) readFrom(src []byte, unsafe bool) e
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -32499,6 +33371,15 @@ func (v *OffsetDeleteRequest) RequestWith(ctx context.Context, r Requestor) (*Of
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *OffsetDeleteRequest) ReadResponseFrom(body []byte) (*OffsetDeleteResponse, error) {
+	resp := &OffsetDeleteResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *OffsetDeleteRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -32761,14 +33642,14 @@ func (v *OffsetDeleteResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *OffsetDeleteResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *OffsetDeleteResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *OffsetDeleteResponse) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetDeleteResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -32777,10 +33658,16 @@ func (v *OffsetDeleteResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -32838,6 +33725,9 @@ func (v *OffsetDeleteResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	return b.Complete()
 }
@@ -32932,6 +33822,15 @@ func (v *DescribeClientQuotasRequest) RequestWith(ctx context.Context, r Request
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeClientQuotasRequest) ReadResponseFrom(body []byte) (*DescribeClientQuotasResponse, error) {
+	resp := &DescribeClientQuotasResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeClientQuotasRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -33316,14 +34215,14 @@ func (v *DescribeClientQuotasResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeClientQuotasResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeClientQuotasResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeClientQuotasResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -33334,10 +34233,16 @@ func (v *DescribeClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v *string
@@ -33355,6 +34260,9 @@ func (v *DescribeClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	{
 		v := s.Entries
@@ -33494,6 +34402,9 @@ func (v *DescribeClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Entries = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Entries")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -33634,6 +34545,15 @@ func (v *AlterClientQuotasRequest) RequestWith(ctx context.Context, r Requestor)
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *AlterClientQuotasRequest) ReadResponseFrom(body []byte) (*AlterClientQuotasResponse, error) {
+	resp := &AlterClientQuotasResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *AlterClientQuotasRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -34070,14 +34990,14 @@ func (v *AlterClientQuotasResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *AlterClientQuotasResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *AlterClientQuotasResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *AlterClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterClientQuotasResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -34088,6 +35008,9 @@ func (v *AlterClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Entries
@@ -34197,6 +35120,9 @@ func (v *AlterClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Entries = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Entries")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -34284,6 +35210,15 @@ func (v *DescribeUserSCRAMCredentialsRequest) RequestWith(ctx context.Context, r
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeUserSCRAMCredentialsRequest) ReadResponseFrom(body []byte) (*DescribeUserSCRAMCredentialsResponse, error) {
+	resp := &DescribeUserSCRAMCredentialsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeUserSCRAMCredentialsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -34591,14 +35526,14 @@ func (v *DescribeUserSCRAMCredentialsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeUserSCRAMCredentialsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeUserSCRAMCredentialsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -34609,10 +35544,16 @@ func (v *DescribeUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool)
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v *string
@@ -34630,6 +35571,9 @@ func (v *DescribeUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool)
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	{
 		v := s.Results
@@ -34730,6 +35674,9 @@ func (v *DescribeUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool)
 		}
 		v = a
 		s.Results = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Results")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -34859,6 +35806,15 @@ func (v *AlterUserSCRAMCredentialsRequest) RequestWith(ctx context.Context, r Re
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *AlterUserSCRAMCredentialsRequest) ReadResponseFrom(body []byte) (*AlterUserSCRAMCredentialsResponse, error) {
+	resp := &AlterUserSCRAMCredentialsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *AlterUserSCRAMCredentialsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -35219,14 +36175,14 @@ func (v *AlterUserSCRAMCredentialsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *AlterUserSCRAMCredentialsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *AlterUserSCRAMCredentialsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *AlterUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -35237,6 +36193,9 @@ func (v *AlterUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool) er
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Results
@@ -35302,6 +36261,9 @@ func (v *AlterUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool) er
 		}
 		v = a
 		s.Results = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Results")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -35422,6 +36384,15 @@ func (v *VoteRequest) RequestWith(ctx context.Context, r Requestor) (*VoteRespon
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *VoteRequest) ReadResponseFrom(body []byte) (*VoteResponse, error) {
+	resp := &VoteResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *VoteRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -35798,14 +36769,14 @@ func (v *VoteResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *VoteResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *VoteResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *VoteResponse) readFrom(src []byte, unsafe bool) error {
+func (v *VoteResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -35816,6 +36787,9 @@ func (v *VoteResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Topics
@@ -35907,6 +36881,9 @@ func (v *VoteResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -36013,6 +36990,15 @@ func (v *BeginQuorumEpochRequest) RequestWith(ctx context.Context, r Requestor)
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *BeginQuorumEpochRequest) ReadResponseFrom(body []byte) (*BeginQuorumEpochResponse, error) {
+	resp := &BeginQuorumEpochResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *BeginQuorumEpochRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -36268,14 +37254,14 @@ func (v *BeginQuorumEpochResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *BeginQuorumEpochResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *BeginQuorumEpochResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *BeginQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
+func (v *BeginQuorumEpochResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -36284,6 +37270,9 @@ func (v *BeginQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Topics
@@ -36349,6 +37338,9 @@ func (v *BeginQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	return b.Complete()
 }
@@ -36455,6 +37447,15 @@ func (v *EndQuorumEpochRequest) RequestWith(ctx context.Context, r Requestor) (*
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *EndQuorumEpochRequest) ReadResponseFrom(body []byte) (*EndQuorumEpochResponse, error) {
+	resp := &EndQuorumEpochResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *EndQuorumEpochRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -36737,14 +37738,14 @@ func (v *EndQuorumEpochResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *EndQuorumEpochResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *EndQuorumEpochResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *EndQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
+func (v *EndQuorumEpochResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -36753,6 +37754,9 @@ func (v *EndQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Topics
@@ -36818,6 +37822,9 @@ func (v *EndQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	return b.Complete()
 }
@@ -36958,6 +37965,15 @@ func (v *DescribeQuorumRequest) RequestWith(ctx context.Context, r Requestor) (*
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeQuorumRequest) ReadResponseFrom(body []byte) (*DescribeQuorumResponse, error) {
+	resp := &DescribeQuorumResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeQuorumRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -37344,14 +38360,14 @@ func (v *DescribeQuorumResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeQuorumResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeQuorumResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -37362,6 +38378,9 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Topics
@@ -37539,6 +38558,9 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -37665,6 +38687,15 @@ func (v *AlterPartitionRequest) RequestWith(ctx context.Context, r Requestor) (*
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *AlterPartitionRequest) ReadResponseFrom(body []byte) (*AlterPartitionResponse, error) {
+	resp := &AlterPartitionResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *AlterPartitionRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -38109,14 +39140,14 @@ func (v *AlterPartitionResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *AlterPartitionResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *AlterPartitionResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *AlterPartitionResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterPartitionResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -38127,10 +39158,16 @@ func (v *AlterPartitionResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Topics
@@ -38253,6 +39290,9 @@ func (v *AlterPartitionResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -38367,6 +39407,15 @@ func (v *UpdateFeaturesRequest) RequestWith(ctx context.Context, r Requestor) (*
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *UpdateFeaturesRequest) ReadResponseFrom(body []byte) (*UpdateFeaturesResponse, error) {
+	resp := &UpdateFeaturesResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *UpdateFeaturesRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -38653,14 +39702,14 @@ func (v *UpdateFeaturesResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *UpdateFeaturesResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *UpdateFeaturesResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *UpdateFeaturesResponse) readFrom(src []byte, unsafe bool) error {
+func (v *UpdateFeaturesResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -38671,10 +39720,16 @@ func (v *UpdateFeaturesResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v *string
@@ -38692,6 +39747,9 @@ func (v *UpdateFeaturesResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	{
 		v := s.Results
@@ -38757,6 +39815,9 @@ func (v *UpdateFeaturesResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Results = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Results")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -38825,6 +39886,15 @@ func (v *EnvelopeRequest) RequestWith(ctx context.Context, r Requestor) (*Envelo
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *EnvelopeRequest) ReadResponseFrom(body []byte) (*EnvelopeResponse, error) {
+	resp := &EnvelopeResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *EnvelopeRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -38981,14 +40051,14 @@ func (v *EnvelopeResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *EnvelopeResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *EnvelopeResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *EnvelopeResponse) readFrom(src []byte, unsafe bool) error {
+func (v *EnvelopeResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -39004,10 +40074,16 @@ func (v *EnvelopeResponse) readFrom(src []byte, unsafe bool) error {
 			v = b.NullableBytes()
 		}
 		s.ResponseData = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ResponseData")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -39163,6 +40239,15 @@ func (v *FetchSnapshotRequest) RequestWith(ctx context.Context, r Requestor) (*F
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *FetchSnapshotRequest) ReadResponseFrom(body []byte) (*FetchSnapshotResponse, error) {
+	resp := &FetchSnapshotResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *FetchSnapshotRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -39733,14 +40818,14 @@ func (v *FetchSnapshotResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *FetchSnapshotResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *FetchSnapshotResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
+func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -39751,10 +40836,16 @@ func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.Topics
@@ -39891,6 +40982,9 @@ func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -39954,6 +41048,15 @@ func (v *DescribeClusterRequest) RequestWith(ctx context.Context, r Requestor) (
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeClusterRequest) ReadResponseFrom(body []byte) (*DescribeClusterResponse, error) {
+	resp := &DescribeClusterResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeClusterRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -40180,14 +41283,14 @@ func (v *DescribeClusterResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeClusterResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeClusterResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeClusterResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeClusterResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -40198,10 +41301,16 @@ func (v *DescribeClusterResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v *string
@@ -40219,6 +41328,9 @@ func (v *DescribeClusterResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	{
 		var v string
@@ -40236,10 +41348,16 @@ func (v *DescribeClusterResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ClusterID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ClusterID")
+		}
 	}
 	{
 		v := b.Int32()
 		s.ControllerID = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ControllerID")
+		}
 	}
 	{
 		v := s.Brokers
@@ -40309,10 +41427,16 @@ func (v *DescribeClusterResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Brokers = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Brokers")
+		}
 	}
 	{
 		v := b.Int32()
 		s.ClusterAuthorizedOperations = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ClusterAuthorizedOperations")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -40403,6 +41527,15 @@ func (v *DescribeProducersRequest) RequestWith(ctx context.Context, r Requestor)
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeProducersRequest) ReadResponseFrom(body []byte) (*DescribeProducersResponse, error) {
+	resp := &DescribeProducersResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeProducersRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -40804,14 +41937,14 @@ func (v *DescribeProducersResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeProducersResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeProducersResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeProducersResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeProducersResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -40822,6 +41955,9 @@ func (v *DescribeProducersResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.Topics
@@ -40969,6 +42105,9 @@ func (v *DescribeProducersResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.Topics = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "Topics")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -41106,6 +42245,15 @@ func (v *BrokerRegistrationRequest) RequestWith(ctx context.Context, r Requestor
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *BrokerRegistrationRequest) ReadResponseFrom(body []byte) (*BrokerRegistrationResponse, error) {
+	resp := &BrokerRegistrationResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *BrokerRegistrationRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -41489,14 +42637,14 @@ func (v *BrokerRegistrationResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *BrokerRegistrationResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *BrokerRegistrationResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *BrokerRegistrationResponse) readFrom(src []byte, unsafe bool) error {
+func (v *BrokerRegistrationResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -41507,14 +42655,23 @@ func (v *BrokerRegistrationResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := b.Int64()
 		s.BrokerEpoch = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "BrokerEpoch")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -41591,6 +42748,15 @@ func (v *BrokerHeartbeatRequest) RequestWith(ctx context.Context, r Requestor) (
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *BrokerHeartbeatRequest) ReadResponseFrom(body []byte) (*BrokerHeartbeatResponse, error) {
+	resp := &BrokerHeartbeatResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *BrokerHeartbeatRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -41761,14 +42927,14 @@ func (v *BrokerHeartbeatResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *BrokerHeartbeatResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *BrokerHeartbeatResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *BrokerHeartbeatResponse) readFrom(src []byte, unsafe bool) error {
+func (v *BrokerHeartbeatResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -41779,22 +42945,37 @@ func (v *BrokerHeartbeatResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := b.Bool()
 		s.IsCaughtUp = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "IsCaughtUp")
+		}
 	}
 	{
 		v := b.Bool()
 		s.IsFenced = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "IsFenced")
+		}
 	}
 	{
 		v := b.Bool()
 		s.ShouldShutdown = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ShouldShutdown")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -41857,6 +43038,15 @@ func (v *UnregisterBrokerRequest) RequestWith(ctx context.Context, r Requestor)
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *UnregisterBrokerRequest) ReadResponseFrom(body []byte) (*UnregisterBrokerResponse, error) {
+	resp := &UnregisterBrokerResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *UnregisterBrokerRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -41982,14 +43172,14 @@ func (v *UnregisterBrokerResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *UnregisterBrokerResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *UnregisterBrokerResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *UnregisterBrokerResponse) readFrom(src []byte, unsafe bool) error {
+func (v *UnregisterBrokerResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -42000,10 +43190,16 @@ func (v *UnregisterBrokerResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		var v *string
@@ -42021,6 +43217,9 @@ func (v *UnregisterBrokerResponse) readFrom(src []byte, unsafe bool) error {
 			}
 		}
 		s.ErrorMessage = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorMessage")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -42082,6 +43281,15 @@ func (v *DescribeTransactionsRequest) RequestWith(ctx context.Context, r Request
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *DescribeTransactionsRequest) ReadResponseFrom(body []byte) (*DescribeTransactionsResponse, error) {
+	resp := &DescribeTransactionsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *DescribeTransactionsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -42404,14 +43612,14 @@ func (v *DescribeTransactionsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *DescribeTransactionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *DescribeTransactionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *DescribeTransactionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeTransactionsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -42422,6 +43630,9 @@ func (v *DescribeTransactionsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := s.TransactionStates
@@ -42570,6 +43781,9 @@ func (v *DescribeTransactionsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.TransactionStates = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "TransactionStates")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -42639,6 +43853,15 @@ func (v *ListTransactionsRequest) RequestWith(ctx context.Context, r Requestor)
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *ListTransactionsRequest) ReadResponseFrom(body []byte) (*ListTransactionsResponse, error) {
+	resp := &ListTransactionsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *ListTransactionsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -42924,14 +44147,14 @@ func (v *ListTransactionsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *ListTransactionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *ListTransactionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *ListTransactionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ListTransactionsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -42942,10 +44165,16 @@ func (v *ListTransactionsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := s.UnknownStateFilters
@@ -42982,6 +44211,9 @@ func (v *ListTransactionsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.UnknownStateFilters = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "UnknownStateFilters")
+		}
 	}
 	{
 		v := s.TransactionStates
@@ -43047,6 +44279,9 @@ func (v *ListTransactionsResponse) readFrom(src []byte, unsafe bool) error {
 		}
 		v = a
 		s.TransactionStates = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "TransactionStates")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -43115,6 +44350,15 @@ func (v *AllocateProducerIDsRequest) RequestWith(ctx context.Context, r Requesto
 	return resp, err
 }
 
+// ReadResponseFrom returns a new response at v's version decoded from body,
+// which must not include the response header.
+func (v *AllocateProducerIDsRequest) ReadResponseFrom(body []byte) (*AllocateProducerIDsResponse, error) {
+	resp := &AllocateProducerIDsResponse{Version: v.Version}
+	resp.Default()
+	err := resp.ReadFrom(body)
+	return resp, err
+}
+
 func (v *AllocateProducerIDsRequest) AppendTo(dst []byte) []byte {
 	version := v.Version
 	_ = version
@@ -43255,14 +44499,14 @@ func (v *AllocateProducerIDsResponse) AppendTo(dst []byte) []byte {
 }
 
 func (v *AllocateProducerIDsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(src, false, nil)
 }
 
 func (v *AllocateProducerIDsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(src, true, nil)
 }
 
-func (v *AllocateProducerIDsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AllocateProducerIDsResponse) readFrom(src []byte, unsafe bool, read *[]string) error {
 	v.Default()
 	b := kbin.Reader{Src: src}
 	version := v.Version
@@ -43273,18 +44517,30 @@ func (v *AllocateProducerIDsResponse) readFrom(src []byte, unsafe bool) error {
 	{
 		v := b.Int32()
 		s.ThrottleMillis = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ThrottleMillis")
+		}
 	}
 	{
 		v := b.Int16()
 		s.ErrorCode = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ErrorCode")
+		}
 	}
 	{
 		v := b.Int64()
 		s.ProducerIDStart = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ProducerIDStart")
+		}
 	}
 	{
 		v := b.Int32()
 		s.ProducerIDLen = v
+		if read != nil && b.Ok() {
+			*read = append(*read, "ProducerIDLen")
+		}
 	}
 	if isFlexible {
 		s.UnknownTags = internalReadTags(&b)
@@ -43834,6 +45090,371 @@ func (k Key) Response() Response { return ResponseForKey(int16(k)) }
 // Int16 is an alias for int16(k).
 func (k Key) Int16() int16 { return int16(k) }
 
+// FieldSentinel returns the sentinel value that signifies "unset" for an
+// integer field in the request or response for the given key. The field is
+// the dotted path to the field from the top level struct, for example
+// "Topics.Partitions.CurrentLeaderEpoch" for the FetchRequest. Request fields
+// are checked before response fields; if a path exists in both the request
+// and response, the sentinel is the same.
+//
+// Sentinels are the negative defaults that Kafka uses for fields that are
+// optional or not yet known (e.g., -1 for a leader epoch or producer ID).
+// This returns false if the field does not exist or has no sentinel.
+func FieldSentinel(key int16, field string) (int64, bool) {
+	switch key {
+	case 0:
+		switch field {
+		case "Topics.Partitions.LogAppendTime":
+			return -1, true
+		case "Topics.Partitions.LogStartOffset":
+			return -1, true
+		}
+	case 1:
+		switch field {
+		case "SessionEpoch":
+			return -1, true
+		case "Topics.Partitions.CurrentLeaderEpoch":
+			return -1, true
+		case "Topics.Partitions.LastFetchedEpoch":
+			return -1, true
+		case "Topics.Partitions.LogStartOffset":
+			return -1, true
+		case "Topics.Partitions.LastStableOffset":
+			return -1, true
+		case "Topics.Partitions.DivergingEpoch.Epoch":
+			return -1, true
+		case "Topics.Partitions.DivergingEpoch.EndOffset":
+			return -1, true
+		case "Topics.Partitions.CurrentLeader.LeaderID":
+			return -1, true
+		case "Topics.Partitions.CurrentLeader.LeaderEpoch":
+			return -1, true
+		case "Topics.Partitions.SnapshotID.EndOffset":
+			return -1, true
+		case "Topics.Partitions.SnapshotID.Epoch":
+			return -1, true
+		case "Topics.Partitions.PreferredReadReplica":
+			return -1, true
+		}
+	case 2:
+		switch field {
+		case "ReplicaID":
+			return -1, true
+		case "Topics.Partitions.CurrentLeaderEpoch":
+			return -1, true
+		case "Topics.Partitions.Timestamp":
+			return -1, true
+		case "Topics.Partitions.Offset":
+			return -1, true
+		case "Topics.Partitions.LeaderEpoch":
+			return -1, true
+		}
+	case 3:
+		switch field {
+		case "ControllerID":
+			return -1, true
+		case "Topics.Partitions.LeaderEpoch":
+			return -1, true
+		case "Topics.AuthorizedOperations":
+			return -2147483648, true
+		case "AuthorizedOperations":
+			return -2147483648, true
+		}
+	case 4:
+		switch field {
+		case "BrokerEpoch":
+			return -1, true
+		}
+	case 5:
+		switch field {
+		case "BrokerEpoch":
+			return -1, true
+		case "Topics.PartitionStates.LeaderEpoch":
+			return -1, true
+		}
+	case 6:
+		switch field {
+		case "BrokerEpoch":
+			return -1, true
+		}
+	case 7:
+		switch field {
+		case "BrokerEpoch":
+			return -1, true
+		}
+	case 8:
+		switch field {
+		case "Generation":
+			return -1, true
+		case "RetentionTimeMillis":
+			return -1, true
+		case "Topics.Partitions.Timestamp":
+			return -1, true
+		case "Topics.Partitions.LeaderEpoch":
+			return -1, true
+		}
+	case 9:
+		switch field {
+		case "Topics.Partitions.LeaderEpoch":
+			return -1, true
+		case "Groups.Topics.Partitions.LeaderEpoch":
+			return -1, true
+		}
+	case 11:
+		switch field {
+		case "RebalanceTimeoutMillis":
+			return -1, true
+		case "Generation":
+			return -1, true
+		}
+	case 15:
+		switch field {
+		case "Groups.AuthorizedOperations":
+			return -2147483648, true
+		}
+	case 18:
+		switch field {
+		case "FinalizedFeaturesEpoch":
+			return -1, true
+		}
+	case 19:
+		switch field {
+		case "Topics.NumPartitions":
+			return -1, true
+		case "Topics.ReplicationFactor":
+			return -1, true
+		case "Topics.Configs.Source":
+			return -1, true
+		}
+	case 22:
+		switch field {
+		case "ProducerID":
+			return -1, true
+		case "ProducerEpoch":
+			return -1, true
+		}
+	case 23:
+		switch field {
+		case "ReplicaID":
+			return -2, true
+		case "Topics.Partitions.CurrentLeaderEpoch":
+			return -1, true
+		case "Topics.Partitions.LeaderEpoch":
+			return -1, true
+		case "Topics.Partitions.EndOffset":
+			return -1, true
+		}
+	case 28:
+		switch field {
+		case "Generation":
+			return -1, true
+		case "Topics.Partitions.LeaderEpoch":
+			return -1, true
+		}
+	case 32:
+		switch field {
+		case "Resources.Configs.Source":
+			return -1, true
+		}
+	case 35:
+		switch field {
+		case "Dirs.TotalBytes":
+			return -1, true
+		case "Dirs.UsableBytes":
+			return -1, true
+		}
+	case 55:
+		switch field {
+		case "Topics.Partitions.CurrentVoters.LastFetchTimestamp":
+			return -1, true
+		case "Topics.Partitions.CurrentVoters.LastCaughtUpTimestamp":
+			return -1, true
+		case "Topics.Partitions.Observers.LastFetchTimestamp":
+			return -1, true
+		case "Topics.Partitions.Observers.LastCaughtUpTimestamp":
+			return -1, true
+		}
+	case 56:
+		switch field {
+		case "BrokerEpoch":
+			return -1, true
+		}
+	case 59:
+		switch field {
+		case "ReplicaID":
+			return -1, true
+		}
+	case 60:
+		switch field {
+		case "ControllerID":
+			return -1, true
+		case "ClusterAuthorizedOperations":
+			return -2147483648, true
+		}
+	case 61:
+		switch field {
+		case "Topics.Partitions.ActiveProducers.LastSequence":
+			return -1, true
+		case "Topics.Partitions.ActiveProducers.LastTimestamp":
+			return -1, true
+		case "Topics.Partitions.ActiveProducers.CurrentTxnStartOffset":
+			return -1, true
+		}
+	case 62:
+		switch field {
+		case "BrokerEpoch":
+			return -1, true
+		}
+	case 63:
+		switch field {
+		case "BrokerEpoch":
+			return -1, true
+		}
+	case 67:
+		switch field {
+		case "BrokerEpoch":
+			return -1, true
+		}
+	}
+	return 0, false
+}
+
+// KIPFor returns the Kafka Improvement Proposals (e.g. "KIP-320") that are
+// referenced in the documentation of the request and response for the given
+// key, in numeric order. This returns nil if the key is unknown or if no KIPs
+// are referenced.
+func KIPFor(key int16) []string {
+	switch key {
+	case 0:
+		return []string{"KIP-360", "KIP-467"}
+	case 1:
+		return []string{"KIP-107", "KIP-227", "KIP-320", "KIP-392"}
+	case 2:
+		return []string{"KIP-207", "KIP-320", "KIP-734"}
+	case 3:
+		return []string{"KIP-78", "KIP-112", "KIP-320", "KIP-430"}
+	case 4:
+		return []string{"KIP-380", "KIP-455", "KIP-866"}
+	case 5:
+		return []string{"KIP-380", "KIP-570", "KIP-866"}
+	case 6:
+		return []string{"KIP-380", "KIP-866"}
+	case 7:
+		return []string{"KIP-380"}
+	case 8:
+		return []string{"KIP-211", "KIP-320", "KIP-345"}
+	case 9:
+		return []string{"KIP-320", "KIP-447"}
+	case 10:
+		return []string{"KIP-699"}
+	case 11:
+		return []string{"KIP-54", "KIP-345", "KIP-394", "KIP-800", "KIP-814"}
+	case 12:
+		return []string{"KIP-345"}
+	case 13:
+		return []string{"KIP-800"}
+	case 14:
+		return []string{"KIP-345"}
+	case 15:
+		return []string{"KIP-345", "KIP-430"}
+	case 16:
+		return []string{"KIP-518"}
+	case 18:
+		return []string{"KIP-511", "KIP-584"}
+	case 19:
+		return []string{"KIP-464", "KIP-525"}
+	case 20:
+		return []string{"KIP-322"}
+	case 21:
+		return []string{"KIP-107"}
+	case 22:
+		return []string{"KIP-360"}
+	case 23:
+		return []string{"KIP-320", "KIP-392"}
+	case 28:
+		return []string{"KIP-320", "KIP-345", "KIP-447"}
+	case 30:
+		return []string{"KIP-252"}
+	case 33:
+		return []string{"KIP-339"}
+	case 35:
+		return []string{"KIP-113"}
+	case 42:
+		return []string{"KIP-229"}
+	case 43:
+		return []string{"KIP-183", "KIP-460"}
+	case 44:
+		return []string{"KIP-339", "KIP-412"}
+	case 45:
+		return []string{"KIP-455"}
+	case 46:
+		return []string{"KIP-455"}
+	case 47:
+		return []string{"KIP-496"}
+	case 48:
+		return []string{"KIP-546"}
+	case 49:
+		return []string{"KIP-546"}
+	case 50:
+		return []string{"KIP-500", "KIP-554"}
+	case 51:
+		return []string{"KIP-500", "KIP-554"}
+	case 52:
+		return []string{"KIP-595"}
+	case 53:
+		return []string{"KIP-595"}
+	case 54:
+		return []string{"KIP-595"}
+	case 55:
+		return []string{"KIP-595", "KIP-642"}
+	case 56:
+		return []string{"KIP-497"}
+	case 57:
+		return []string{"KIP-584"}
+	case 58:
+		return []string{"KIP-590"}
+	case 59:
+		return []string{"KIP-630"}
+	case 60:
+		return []string{"KIP-700"}
+	case 61:
+		return []string{"KIP-664"}
+	case 62:
+		return []string{"KIP-500", "KIP-631"}
+	case 63:
+		return []string{"KIP-500", "KIP-631"}
+	case 64:
+		return []string{"KIP-500", "KIP-631"}
+	case 65:
+		return []string{"KIP-664"}
+	case 66:
+		return []string{"KIP-664"}
+	case 67:
+		return []string{"KIP-730"}
+	}
+	return nil
+}
+
+// modeledTags returns the tag keys of the tagged fields of v, which is a
+// pointer to a struct in this package, or nil if v has no tagged fields.
+func modeledTags(v interface{}) []uint32 {
+	switch v.(type) {
+	case *FetchRequest:
+		return []uint32{0}
+	case *FetchResponseTopicPartition:
+		return []uint32{0, 1, 2}
+	case *ApiVersionsResponse:
+		return []uint32{0, 1, 2, 3}
+	case *CreateTopicsResponseTopic:
+		return []uint32{0}
+	case *FetchSnapshotRequest:
+		return []uint32{0}
+	case *FetchSnapshotResponseTopicPartition:
+		return []uint32{0}
+	}
+	return nil
+}
+
 // A type of config.
 //
 // Possible values and their meanings:
@@ -0,0 +1,56 @@
+package kmsg
+
+import "testing"
+
+func TestFieldSentinel(t *testing.T) {
+	for _, test := range []struct {
+		key    Key
+		field  string
+		exp    int64
+		expSet bool
+	}{
+		{Fetch, "Topics.Partitions.CurrentLeaderEpoch", -1, true},   // request
+		{Fetch, "Topics.Partitions.PreferredReadReplica", -1, true}, // response
+		{Fetch, "Topics.Partitions.LogStartOffset", -1, true},       // both
+		{ListOffsets, "ReplicaID", -1, true},
+		{InitProducerID, "ProducerID", -1, true},
+		{InitProducerID, "ProducerEpoch", -1, true},
+		{DescribeConfigs, "Resources.Configs.Source", -1, true},
+
+		{Fetch, "Topics.Partitions.Partition", 0, false},
+		{Fetch, "NotAField", 0, false},
+		{Key(-1), "ReplicaID", 0, false},
+	} {
+		got, set := FieldSentinel(int16(test.key), test.field)
+		if got != test.exp || set != test.expSet {
+			t.Errorf("%s %s: got %d, %v != exp %d, %v", test.key.Name(), test.field, got, set, test.exp, test.expSet)
+		}
+	}
+}
+
+func TestReadResponseFrom(t *testing.T) {
+	req := NewPtrMetadataRequest()
+	req.SetVersion(9) // first flexible version
+
+	exp := NewPtrMetadataResponse()
+	exp.SetVersion(9)
+	exp.ClusterID = StringPtr("cluster")
+	exp.ControllerID = 3
+
+	resp, err := req.ReadResponseFrom(exp.AppendTo(nil))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if resp.GetVersion() != 9 || !resp.IsFlexible() {
+		t.Errorf("got version %d (flexible? %v), exp 9 (flexible)", resp.GetVersion(), resp.IsFlexible())
+	}
+	if resp.ClusterID == nil || *resp.ClusterID != "cluster" || resp.ControllerID != 3 {
+		t.Errorf("got unexpected response %v", resp)
+	}
+
+	// The v9 response cannot be decoded at v8, which is not flexible.
+	req.SetVersion(8)
+	if _, err := req.ReadResponseFrom(exp.AppendTo(nil)); err == nil {
+		t.Error("expected error decoding flexible response at non-flexible version")
+	}
+}
@@ -0,0 +1,392 @@
+package kmsg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BuildOffsetFetch returns an OffsetFetchRequest for the given groups, which
+// is a map of groups to topics to partitions. A nil topic map for a group
+// fetches offsets for all topics the group has committed. Groups and topics
+// are sorted in the request so that the request is deterministic.
+//
+// OffsetFetch v8 switched from a single Group with Topics to a batched Groups
+// array. This function populates both shapes so that the request can be issued
+// at any version: v8+ requests all groups, while v0-v7 requests only the
+// first (sorted) group. If you are issuing the request at v0-v7 with more than
+// one group, you must split the request yourself.
+//
+// RequireStable is only serialized in v7+.
+func BuildOffsetFetch(requireStable bool, groups map[string]map[string][]int32) *OffsetFetchRequest {
+	req := NewPtrOffsetFetchRequest()
+	req.RequireStable = requireStable
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+
+	for i, group := range names {
+		topics := groups[group]
+		rg := NewOffsetFetchRequestGroup()
+		rg.Group = group
+		if topics != nil {
+			rg.Topics = make([]OffsetFetchRequestGroupTopic, 0, len(topics))
+			for topic, partitions := range topics {
+				rt := NewOffsetFetchRequestGroupTopic()
+				rt.Topic = topic
+				rt.Partitions = partitions
+				rg.Topics = append(rg.Topics, rt)
+			}
+			sort.Slice(rg.Topics, func(i, j int) bool { return rg.Topics[i].Topic < rg.Topics[j].Topic })
+		}
+		req.Groups = append(req.Groups, rg)
+
+		if i == 0 {
+			req.Group = group
+			if rg.Topics != nil {
+				req.Topics = make([]OffsetFetchRequestTopic, 0, len(rg.Topics))
+				for _, t := range rg.Topics {
+					rt := NewOffsetFetchRequestTopic()
+					rt.Topic = t.Topic
+					rt.Partitions = t.Partitions
+					req.Topics = append(req.Topics, rt)
+				}
+			}
+		}
+	}
+	return req
+}
+
+// GroupFor returns the offsets for the given group, smoothing over the v8
+// switch from top level Topics to a batched Groups array. The ErrorCodes in
+// the returned group can be converted to an error with kerr.ErrorForCode.
+//
+// For v8+ responses, this returns false if the group is not in the response.
+// Responses prior to v8 do not contain the group: the response is for the
+// single group that was requested, and this always returns the top level
+// fields converted to the v8 shape with the Group set to the input group.
+func (v *OffsetFetchResponse) GroupFor(group string) (OffsetFetchResponseGroup, bool) {
+	if v.Version < 8 {
+		g := NewOffsetFetchResponseGroup()
+		g.Group = group
+		g.ErrorCode = v.ErrorCode
+		for _, t := range v.Topics {
+			gt := NewOffsetFetchResponseGroupTopic()
+			gt.Topic = t.Topic
+			for _, p := range t.Partitions {
+				gp := NewOffsetFetchResponseGroupTopicPartition()
+				gp.Partition = p.Partition
+				gp.Offset = p.Offset
+				gp.LeaderEpoch = p.LeaderEpoch
+				gp.Metadata = p.Metadata
+				gp.ErrorCode = p.ErrorCode
+				gt.Partitions = append(gt.Partitions, gp)
+			}
+			g.Topics = append(g.Topics, gt)
+		}
+		return g, true
+	}
+	for _, g := range v.Groups {
+		if g.Group == group {
+			return g, true
+		}
+	}
+	return OffsetFetchResponseGroup{}, false
+}
+
+// GroupMember identifies a member of a group for BuildLeaveGroup. InstanceID
+// is the member's static group instance ID (KIP-345), if any.
+type GroupMember struct {
+	MemberID   string
+	InstanceID string
+}
+
+// BuildHeartbeat returns a HeartbeatRequest for the given group member. An
+// empty instanceID means the member is not a static member, and the
+// InstanceID field is left null.
+func BuildHeartbeat(group string, gen int32, memberID, instanceID string) *HeartbeatRequest {
+	req := NewPtrHeartbeatRequest()
+	req.Group = group
+	req.Generation = gen
+	req.MemberID = memberID
+	if instanceID != "" {
+		req.InstanceID = &instanceID
+	}
+	return req
+}
+
+// BuildLeaveGroup returns a LeaveGroupRequest for the given members of group.
+// An empty InstanceID in a member leaves that member's InstanceID null.
+//
+// LeaveGroup v3 switched from a single MemberID to a batched Members array.
+// This function populates both fields so that the request can be issued at
+// any version: v3+ removes all members, while v0-v2 removes only the first
+// member. If you are issuing the request at v0-v2 with more than one member,
+// you must split the request yourself.
+func BuildLeaveGroup(group string, members ...GroupMember) *LeaveGroupRequest {
+	req := NewPtrLeaveGroupRequest()
+	req.Group = group
+	if len(members) > 0 {
+		req.MemberID = members[0].MemberID
+	}
+	for _, m := range members {
+		rm := NewLeaveGroupRequestMember()
+		rm.MemberID = m.MemberID
+		if m.InstanceID != "" {
+			instanceID := m.InstanceID
+			rm.InstanceID = &instanceID
+		}
+		req.Members = append(req.Members, rm)
+	}
+	return req
+}
+
+// StaticMember is the identity of a static group member (KIP-345). A static
+// member has a stable InstanceID across restarts, which allows the member to
+// rejoin the group without triggering a rebalance. An empty InstanceID means
+// the member is not static, and the InstanceID field of requests is left
+// null.
+type StaticMember struct {
+	Group      string
+	InstanceID string
+	MemberID   string
+}
+
+// ApplyTo sets the group, member ID, and instance ID fields of r, returning
+// whether r is a group request that was modified. This supports JoinGroup,
+// Heartbeat, SyncGroup, LeaveGroup, OffsetCommit, and TxnOffsetCommit
+// requests. For LeaveGroup, the Members array is replaced with only this
+// member.
+//
+// The instance ID is only serialized at the versions of each request that
+// support static membership (JoinGroup v5+, Heartbeat v3+, SyncGroup v3+,
+// LeaveGroup v3+, OffsetCommit v7+, TxnOffsetCommit v3+).
+func (m StaticMember) ApplyTo(r Request) bool {
+	var instanceID *string
+	if m.InstanceID != "" {
+		id := m.InstanceID
+		instanceID = &id
+	}
+	switch r := r.(type) {
+	case *JoinGroupRequest:
+		r.Group, r.MemberID, r.InstanceID = m.Group, m.MemberID, instanceID
+	case *HeartbeatRequest:
+		r.Group, r.MemberID, r.InstanceID = m.Group, m.MemberID, instanceID
+	case *SyncGroupRequest:
+		r.Group, r.MemberID, r.InstanceID = m.Group, m.MemberID, instanceID
+	case *LeaveGroupRequest:
+		r.Group, r.MemberID = m.Group, m.MemberID
+		rm := NewLeaveGroupRequestMember()
+		rm.MemberID, rm.InstanceID = m.MemberID, instanceID
+		r.Members = []LeaveGroupRequestMember{rm}
+	case *OffsetCommitRequest:
+		r.Group, r.MemberID, r.InstanceID = m.Group, m.MemberID, instanceID
+	case *TxnOffsetCommitRequest:
+		r.Group, r.MemberID, r.InstanceID = m.Group, m.MemberID, instanceID
+	default:
+		return false
+	}
+	return true
+}
+
+// ComputeLag returns the lag of each partition of a group, which is a map of
+// topics to partitions to the difference between the partition's end offset
+// in endOffsets (a ListOffsets response for the latest offsets) and the
+// group's committed offset in committed. A committed offset past the end
+// offset, which can happen if the offsets are fetched at different times,
+// has a lag of 0.
+//
+// Partitions in endOffsets that the group has no committed offset for have a
+// lag of -1, meaning the lag is unknown: the group has not consumed the
+// partition, and where the group starts consuming depends on its offset reset
+// policy. Partitions the group has committed that are not in endOffsets are
+// not returned.
+//
+// If either response has a partition error, the partition is not returned and
+// this returns an error listing every failed partition along with the lag of
+// every other partition. If the group as a whole failed, this returns only an
+// error. Error codes can be converted to errors with kerr.ErrorForCode.
+//
+// For v8+ OffsetFetch responses, committed must contain exactly one group.
+func ComputeLag(committed *OffsetFetchResponse, endOffsets *ListOffsetsResponse) (map[string]map[int32]int64, error) {
+	var g OffsetFetchResponseGroup
+	if committed.Version >= 8 {
+		if len(committed.Groups) != 1 {
+			return nil, fmt.Errorf("unable to compute lag: offset fetch response has %d groups, expected 1", len(committed.Groups))
+		}
+		g = committed.Groups[0]
+	} else {
+		g, _ = committed.GroupFor("")
+	}
+	if g.ErrorCode != 0 {
+		return nil, fmt.Errorf("unable to compute lag: offset fetch failed with error code %d", g.ErrorCode)
+	}
+
+	var failed []string
+	type commit struct {
+		offset int64
+		failed bool
+	}
+	commits := make(map[string]map[int32]commit, len(g.Topics))
+	for _, t := range g.Topics {
+		ps := make(map[int32]commit, len(t.Partitions))
+		for _, p := range t.Partitions {
+			if p.ErrorCode != 0 {
+				failed = append(failed, fmt.Sprintf("%s[%d] offset fetch error code %d", t.Topic, p.Partition, p.ErrorCode))
+				ps[p.Partition] = commit{failed: true}
+				continue
+			}
+			ps[p.Partition] = commit{offset: p.Offset}
+		}
+		commits[t.Topic] = ps
+	}
+
+	ends, endErrs := endOffsets.OffsetMap()
+	for topic, ps := range endErrs {
+		for partition, err := range ps {
+			failed = append(failed, fmt.Sprintf("%s[%d] list offsets failed: %v", topic, partition, err))
+		}
+	}
+
+	lags := make(map[string]map[int32]int64, len(ends))
+	for topic, ps := range ends {
+		for partition, end := range ps {
+			c, ok := commits[topic][partition]
+			if c.failed {
+				continue
+			}
+			lag := int64(-1)
+			if ok && c.offset >= 0 {
+				lag = end - c.offset
+				if lag < 0 {
+					lag = 0
+				}
+			}
+			if lags[topic] == nil {
+				lags[topic] = make(map[int32]int64)
+			}
+			lags[topic][partition] = lag
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return lags, fmt.Errorf("unable to compute lag for all partitions: %s", strings.Join(failed, ", "))
+	}
+	return lags, nil
+}
+
+// Group states, as returned in ListGroupsResponse and DescribeGroupsResponse
+// and as used in ListGroupsRequest.StatesFilter.
+const (
+	// GroupStateEmpty is a group that has no members, but may still have
+	// committed offsets.
+	GroupStateEmpty = "Empty"
+	// GroupStatePreparingRebalance is a group that is waiting for members
+	// to join.
+	GroupStatePreparingRebalance = "PreparingRebalance"
+	// GroupStateCompletingRebalance is a group that is waiting for the
+	// leader to sync assignments.
+	GroupStateCompletingRebalance = "CompletingRebalance"
+	// GroupStateStable is a group whose members have their assignments.
+	GroupStateStable = "Stable"
+	// GroupStateDead is a group that has no members and no metadata, and
+	// is being removed.
+	GroupStateDead = "Dead"
+)
+
+// BuildListGroups returns a ListGroupsRequest listing groups in any of the
+// given states, such as GroupStateStable. No states lists all groups.
+//
+// The states filter is only serialized in v4+ (KIP-518); prior versions
+// always list all groups, and it is up to the caller to filter the response.
+func BuildListGroups(states ...string) *ListGroupsRequest {
+	req := NewPtrListGroupsRequest()
+	req.StatesFilter = append(req.StatesFilter, states...)
+	return req
+}
+
+// GroupStates returns a map of each group in the response to its state. The
+// state is empty for responses prior to v4, which do not contain the state.
+func (v *ListGroupsResponse) GroupStates() map[string]string {
+	states := make(map[string]string, len(v.Groups))
+	for _, g := range v.Groups {
+		states[g.Group] = g.GroupState
+	}
+	return states
+}
+
+// BuildOffsetDelete returns an OffsetDeleteRequest deleting the committed
+// offsets of group for the given topics and partitions (KIP-496). Topics are
+// sorted in the request so that the request is deterministic; partitions are
+// kept in the order given.
+func BuildOffsetDelete(group string, topics map[string][]int32) *OffsetDeleteRequest {
+	req := NewPtrOffsetDeleteRequest()
+	req.Group = group
+	for topic, partitions := range topics {
+		rt := NewOffsetDeleteRequestTopic()
+		rt.Topic = topic
+		for _, partition := range partitions {
+			rp := NewOffsetDeleteRequestTopicPartition()
+			rp.Partition = partition
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		req.Topics = append(req.Topics, rt)
+	}
+	sort.Slice(req.Topics, func(i, j int) bool { return req.Topics[i].Topic < req.Topics[j].Topic })
+	return req
+}
+
+// Results returns a map of topics to partitions to the result of deleting the
+// partition's committed offset: nil if the offset was deleted, or otherwise a
+// PartitionFailure, the code of which can be converted to a kerr error with
+// kerr.ErrorForCode. The broker refuses to delete the offsets of a topic that
+// the group is actively subscribed to with GROUP_SUBSCRIBED_TO_TOPIC.
+//
+// If the request failed as a whole, the response has a top level ErrorCode and
+// no topics; the top level ErrorCode should be checked before the results.
+func (v *OffsetDeleteResponse) Results() map[string]map[int32]error {
+	results := make(map[string]map[int32]error, len(v.Topics))
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		ps := results[t.Topic]
+		if ps == nil {
+			ps = make(map[int32]error, len(t.Partitions))
+			results[t.Topic] = ps
+		}
+		for _, p := range t.Partitions {
+			var err error
+			if p.ErrorCode != 0 {
+				err = PartitionFailure{Topic: t.Topic, Partition: p.Partition, ErrorCode: p.ErrorCode}
+			}
+			ps[p.Partition] = err
+		}
+	}
+	return results
+}
+
+// BuildDeleteGroups returns a DeleteGroupsRequest deleting the given groups.
+func BuildDeleteGroups(groups ...string) *DeleteGroupsRequest {
+	req := NewPtrDeleteGroupsRequest()
+	req.Groups = append(req.Groups, groups...)
+	return req
+}
+
+// Results returns a map of each group in the response to the result of
+// deleting it: nil if the group was deleted, or otherwise a GroupFailure, the
+// code of which can be converted to a kerr error with kerr.ErrorForCode. The
+// broker refuses to delete a group that still has members with
+// NON_EMPTY_GROUP.
+func (v *DeleteGroupsResponse) Results() map[string]error {
+	results := make(map[string]error, len(v.Groups))
+	for _, g := range v.Groups {
+		var err error
+		if g.ErrorCode != 0 {
+			err = GroupFailure{Group: g.Group, ErrorCode: g.ErrorCode}
+		}
+		results[g.Group] = err
+	}
+	return results
+}
@@ -0,0 +1,303 @@
+package kmsg
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildOffsetFetch(t *testing.T) {
+	req := BuildOffsetFetch(true, map[string]map[string][]int32{
+		"g2": nil,
+		"g1": {"b": {1}, "a": {0, 2}},
+	})
+
+	// At v7, only the first group is serialized.
+	req.SetVersion(7)
+	var v7 OffsetFetchRequest
+	v7.SetVersion(7)
+	if err := v7.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read v7: %v", err)
+	}
+	expTopics := []OffsetFetchRequestTopic{
+		{Topic: "a", Partitions: []int32{0, 2}},
+		{Topic: "b", Partitions: []int32{1}},
+	}
+	if v7.Group != "g1" || !v7.RequireStable || !reflect.DeepEqual(v7.Topics, expTopics) || len(v7.Groups) != 0 {
+		t.Errorf("v7: got unexpected group %q, require stable %v, topics %v, groups %v", v7.Group, v7.RequireStable, v7.Topics, v7.Groups)
+	}
+
+	// At v8, all groups are serialized.
+	req.SetVersion(8)
+	var v8 OffsetFetchRequest
+	v8.SetVersion(8)
+	if err := v8.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read v8: %v", err)
+	}
+	expGroups := []OffsetFetchRequestGroup{
+		{Group: "g1", Topics: []OffsetFetchRequestGroupTopic{
+			{Topic: "a", Partitions: []int32{0, 2}},
+			{Topic: "b", Partitions: []int32{1}},
+		}},
+		{Group: "g2"},
+	}
+	if !reflect.DeepEqual(v8.Groups, expGroups) || v8.Group != "" || !v8.RequireStable {
+		t.Errorf("v8: got unexpected group %q, groups %v", v8.Group, v8.Groups)
+	}
+}
+
+func TestOffsetFetchGroupFor(t *testing.T) {
+	v7 := NewPtrOffsetFetchResponse()
+	v7.SetVersion(7)
+	v7.Topics = []OffsetFetchResponseTopic{{
+		Topic: "a",
+		Partitions: []OffsetFetchResponseTopicPartition{
+			{Partition: 0, Offset: 10, LeaderEpoch: 1},
+			{Partition: 1, Offset: -1, LeaderEpoch: -1, ErrorCode: 3},
+		},
+	}}
+	g, ok := v7.GroupFor("g1")
+	exp := OffsetFetchResponseGroup{
+		Group: "g1",
+		Topics: []OffsetFetchResponseGroupTopic{{
+			Topic: "a",
+			Partitions: []OffsetFetchResponseGroupTopicPartition{
+				{Partition: 0, Offset: 10, LeaderEpoch: 1},
+				{Partition: 1, Offset: -1, LeaderEpoch: -1, ErrorCode: 3},
+			},
+		}},
+	}
+	if !ok || !reflect.DeepEqual(g, exp) {
+		t.Errorf("v7: got %v (ok? %v) != exp %v", g, ok, exp)
+	}
+
+	v8 := NewPtrOffsetFetchResponse()
+	v8.SetVersion(8)
+	v8.Groups = []OffsetFetchResponseGroup{
+		{Group: "g1", ErrorCode: 16},
+		exp,
+	}
+	v8.Groups[1].Group = "g2"
+	if g, ok := v8.GroupFor("g1"); !ok || g.ErrorCode != 16 {
+		t.Errorf("v8 g1: got unexpected %v (ok? %v)", g, ok)
+	}
+	if g, ok := v8.GroupFor("g2"); !ok || !reflect.DeepEqual(g.Topics, exp.Topics) {
+		t.Errorf("v8 g2: got unexpected %v (ok? %v)", g, ok)
+	}
+	if _, ok := v8.GroupFor("missing"); ok {
+		t.Error("v8: unexpectedly found missing group")
+	}
+}
+
+func TestBuildHeartbeat(t *testing.T) {
+	req := BuildHeartbeat("g", 3, "m", "")
+	if req.Group != "g" || req.Generation != 3 || req.MemberID != "m" || req.InstanceID != nil {
+		t.Errorf("got unexpected %v", req)
+	}
+	req = BuildHeartbeat("g", 3, "m", "i")
+	if req.InstanceID == nil || *req.InstanceID != "i" {
+		t.Errorf("got unexpected instance ID %v", req.InstanceID)
+	}
+}
+
+func TestBuildLeaveGroup(t *testing.T) {
+	req := BuildLeaveGroup("g", GroupMember{MemberID: "m1"}, GroupMember{MemberID: "m2", InstanceID: "i2"})
+
+	// At v2, only the first member is serialized.
+	req.SetVersion(2)
+	var v2 LeaveGroupRequest
+	v2.SetVersion(2)
+	if err := v2.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read v2: %v", err)
+	}
+	if v2.Group != "g" || v2.MemberID != "m1" || len(v2.Members) != 0 {
+		t.Errorf("v2: got unexpected group %q, member %q, members %v", v2.Group, v2.MemberID, v2.Members)
+	}
+
+	// At v3, all members are serialized.
+	req.SetVersion(3)
+	var v3 LeaveGroupRequest
+	v3.SetVersion(3)
+	if err := v3.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read v3: %v", err)
+	}
+	i2 := "i2"
+	exp := []LeaveGroupRequestMember{
+		{MemberID: "m1"},
+		{MemberID: "m2", InstanceID: &i2},
+	}
+	if !reflect.DeepEqual(v3.Members, exp) || v3.MemberID != "" {
+		t.Errorf("v3: got unexpected member %q, members %v", v3.MemberID, v3.Members)
+	}
+}
+
+func TestStaticMemberApplyTo(t *testing.T) {
+	m := StaticMember{Group: "g", InstanceID: "i", MemberID: "m"}
+
+	join := NewPtrJoinGroupRequest()
+	if !m.ApplyTo(join) || join.Group != "g" || join.MemberID != "m" || join.InstanceID == nil || *join.InstanceID != "i" {
+		t.Errorf("join: got unexpected %v", join)
+	}
+
+	commit := NewPtrOffsetCommitRequest()
+	if !m.ApplyTo(commit) || commit.Group != "g" || commit.MemberID != "m" || commit.InstanceID == nil || *commit.InstanceID != "i" {
+		t.Errorf("commit: got unexpected %v", commit)
+	}
+	*commit.InstanceID = "changed"
+	if *join.InstanceID != "i" {
+		t.Error("instance IDs unexpectedly alias across requests")
+	}
+
+	dynamic := StaticMember{Group: "g", MemberID: "m"}
+	if !dynamic.ApplyTo(commit) || commit.InstanceID != nil {
+		t.Errorf("commit: got instance ID %v for a dynamic member, expected nil", commit.InstanceID)
+	}
+
+	if m.ApplyTo(NewPtrMetadataRequest()) {
+		t.Error("unexpectedly applied to a metadata request")
+	}
+}
+
+func TestComputeLag(t *testing.T) {
+	committed := NewPtrOffsetFetchResponse()
+	committed.SetVersion(7)
+	committed.Topics = []OffsetFetchResponseTopic{{
+		Topic: "foo",
+		Partitions: []OffsetFetchResponseTopicPartition{
+			{Partition: 0, Offset: 10},
+			{Partition: 1, Offset: -1}, // no commit
+			{Partition: 2, Offset: 50},
+			{Partition: 3, Offset: -1, ErrorCode: 3},
+		},
+	}}
+
+	ends := NewPtrListOffsetsResponse()
+	ends.SetVersion(4)
+	ends.Topics = []ListOffsetsResponseTopic{{
+		Topic: "foo",
+		Partitions: []ListOffsetsResponseTopicPartition{
+			{Partition: 0, Offset: 15},
+			{Partition: 1, Offset: 7},
+			{Partition: 2, Offset: 40}, // behind the commit
+			{Partition: 3, Offset: 9},
+			{Partition: 4, Offset: 3}, // not committed at all
+			{Partition: 5, Offset: -1, ErrorCode: 6},
+		},
+	}}
+
+	lags, err := ComputeLag(committed, ends)
+	if err == nil || !strings.Contains(err.Error(), "foo[3]") || !strings.Contains(err.Error(), "foo[5]") {
+		t.Errorf("got err %v, expected an error for partitions 3 and 5", err)
+	}
+	exp := map[string]map[int32]int64{"foo": {0: 5, 1: -1, 2: 0, 4: -1}}
+	if !reflect.DeepEqual(lags, exp) {
+		t.Errorf("got lags %v != exp %v", lags, exp)
+	}
+
+	// v0 end offsets are read from OldStyleOffsets.
+	ends.SetVersion(0)
+	ends.Topics[0].Partitions = []ListOffsetsResponseTopicPartition{{Partition: 0, OldStyleOffsets: []int64{18}}}
+	if lags, _ := ComputeLag(committed, ends); !reflect.DeepEqual(lags, map[string]map[int32]int64{"foo": {0: 8}}) {
+		t.Errorf("v0: got lags %v, expected only foo[0] lag 8", lags)
+	}
+
+	committed.ErrorCode = 16
+	if _, err := ComputeLag(committed, ends); err == nil {
+		t.Error("expected an error for a failed offset fetch")
+	}
+}
+
+func TestBuildListGroups(t *testing.T) {
+	req := BuildListGroups(GroupStateStable, GroupStateEmpty)
+
+	// At v4, the filter is serialized.
+	req.SetVersion(4)
+	var v4 ListGroupsRequest
+	v4.SetVersion(4)
+	if err := v4.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read v4: %v", err)
+	}
+	if exp := []string{"Stable", "Empty"}; !reflect.DeepEqual(v4.StatesFilter, exp) {
+		t.Errorf("v4: got states filter %v != exp %v", v4.StatesFilter, exp)
+	}
+
+	// At v3, the filter is omitted.
+	req.SetVersion(3)
+	var v3 ListGroupsRequest
+	v3.SetVersion(3)
+	if err := v3.ReadFrom(req.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read v3: %v", err)
+	}
+	if len(v3.StatesFilter) != 0 {
+		t.Errorf("v3: got unexpected states filter %v", v3.StatesFilter)
+	}
+}
+
+func TestListGroupsResponseGroupStates(t *testing.T) {
+	resp := NewPtrListGroupsResponse()
+	resp.SetVersion(4)
+	resp.Groups = []ListGroupsResponseGroup{
+		{Group: "g1", ProtocolType: "consumer", GroupState: GroupStateStable},
+		{Group: "g2", ProtocolType: "consumer", GroupState: GroupStateEmpty},
+	}
+	exp := map[string]string{"g1": "Stable", "g2": "Empty"}
+	if got := resp.GroupStates(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+}
+
+func TestBuildOffsetDelete(t *testing.T) {
+	req := BuildOffsetDelete("g", map[string][]int32{"b": {1}, "a": {2, 0}})
+	exp := []OffsetDeleteRequestTopic{
+		{Topic: "a", Partitions: []OffsetDeleteRequestTopicPartition{{Partition: 2}, {Partition: 0}}},
+		{Topic: "b", Partitions: []OffsetDeleteRequestTopicPartition{{Partition: 1}}},
+	}
+	if req.Group != "g" || !reflect.DeepEqual(req.Topics, exp) {
+		t.Errorf("got group %q, topics %v != exp %v", req.Group, req.Topics, exp)
+	}
+}
+
+func TestOffsetDeleteResponseResults(t *testing.T) {
+	resp := NewPtrOffsetDeleteResponse()
+	resp.Topics = []OffsetDeleteResponseTopic{{
+		Topic: "foo",
+		Partitions: []OffsetDeleteResponseTopicPartition{
+			{Partition: 0},
+			{Partition: 1, ErrorCode: 86}, // GROUP_SUBSCRIBED_TO_TOPIC
+		},
+	}}
+
+	results := resp.Results()
+	if len(results["foo"]) != 2 {
+		t.Fatalf("got unexpected results %v", results)
+	}
+	if err, ok := results["foo"][0]; !ok || err != nil {
+		t.Errorf("foo[0]: got err %v (ok? %v), expected success", err, ok)
+	}
+	var failure PartitionFailure
+	if err := results["foo"][1]; !errors.As(err, &failure) || failure.ErrorCode != 86 || failure.Partition != 1 {
+		t.Errorf("foo[1]: got err %v, expected error code 86", err)
+	}
+}
+
+func TestDeleteGroups(t *testing.T) {
+	req := BuildDeleteGroups("g1", "g2")
+	if exp := []string{"g1", "g2"}; !reflect.DeepEqual(req.Groups, exp) {
+		t.Errorf("got groups %v != exp %v", req.Groups, exp)
+	}
+
+	resp := NewPtrDeleteGroupsResponse()
+	resp.Groups = []DeleteGroupsResponseGroup{
+		{Group: "g1"},
+		{Group: "g2", ErrorCode: 68}, // NON_EMPTY_GROUP
+	}
+	results := resp.Results()
+	if err, ok := results["g1"]; !ok || err != nil {
+		t.Errorf("g1: got err %v (ok? %v), expected success", err, ok)
+	}
+	var failure GroupFailure
+	if err := results["g2"]; !errors.As(err, &failure) || failure.ErrorCode != 68 || failure.Group != "g2" {
+		t.Errorf("g2: got err %v, expected error code 68", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"math/bits"
 	"reflect"
+	"strconv"
 	"unsafe"
 )
 
@@ -20,6 +21,28 @@ import (
 // from a slice because the slice did not have enough data.
 var ErrNotEnoughData = errors.New("response did not contain enough data to be valid")
 
+// ErrShort is returned when a type could not fully decode because the input
+// did not have enough data and the total amount of data needed is known.
+// ErrShort is ErrNotEnoughData under errors.Is, so existing checks for
+// ErrNotEnoughData continue to work.
+type ErrShort struct {
+	// Need is the total number of bytes needed to decode the type.
+	Need int
+	// Have is the number of bytes that were available.
+	Have int
+}
+
+func (e *ErrShort) Error() string {
+	return ErrNotEnoughData.Error() + ": need " + strconv.Itoa(e.Need) + " bytes, have " + strconv.Itoa(e.Have)
+}
+
+// Is returns true if target is ErrNotEnoughData.
+func (*ErrShort) Is(target error) bool { return target == ErrNotEnoughData }
+
+// ErrVarintOverflow is returned when a varint has too many continuation bytes
+// or a value that overflows the type being decoded.
+var ErrVarintOverflow = errors.New("varint overflows its type")
+
 // AppendBool appends 1 for true or 0 for false to dst.
 func AppendBool(dst []byte, v bool) []byte {
 	if v {
@@ -88,6 +111,12 @@ func UvarintLen(u uint32) int {
 	return int(uvarintLens[byte(bits.Len32(u))])
 }
 
+// VarlongLen returns how long i would be if it were varlong encoded.
+func VarlongLen(i int64) int {
+	u := uint64(i)<<1 ^ uint64(i>>63)
+	return uvarlongLen(u)
+}
+
 func uvarlongLen(u uint64) int {
 	return int(uvarintLens[byte(bits.Len64(u))])
 }
@@ -619,6 +648,39 @@ func (b *Reader) Varlong() int64 {
 	return val
 }
 
+// VarintChecked returns a varint int32 from the reader, or an error if the
+// varint could not be decoded. Unlike Varint, which returns 0 for both
+// truncated and malformed input, this returns ErrNotEnoughData if the input
+// was truncated and ErrVarintOverflow if the varint overflows an int32.
+func (b *Reader) VarintChecked() (int32, error) {
+	val, n := Varint(b.Src)
+	if n <= 0 {
+		return 0, b.varintErr(n)
+	}
+	b.Src = b.Src[n:]
+	return val, nil
+}
+
+// VarlongChecked returns a varlong int64 from the reader, or an error if the
+// varlong could not be decoded, with the same semantics as VarintChecked.
+func (b *Reader) VarlongChecked() (int64, error) {
+	val, n := Varlong(b.Src)
+	if n <= 0 {
+		return 0, b.varintErr(n)
+	}
+	b.Src = b.Src[n:]
+	return val, nil
+}
+
+func (b *Reader) varintErr(n int) error {
+	b.bad = true
+	b.Src = nil
+	if n < 0 {
+		return ErrVarintOverflow
+	}
+	return ErrNotEnoughData
+}
+
 // Uvarint returns a uvarint encoded uint32 from the reader.
 func (b *Reader) Uvarint() uint32 {
 	val, n := Uvarint(b.Src)
@@ -0,0 +1,44 @@
+package kmsg
+
+import "fmt"
+
+// OffsetMap returns a map of topics to partitions to the offset for each
+// partition in the response, and a map of topics to partitions to the error
+// for each partition that failed. Each partition is in exactly one of the two
+// maps. Partition errors are PartitionFailure values, the code of which can be
+// converted to a kerr error with kerr.ErrorForCode.
+//
+// For v0 responses, the offset is the first of the partition's
+// OldStyleOffsets, and a partition with no offsets is returned as an error.
+func (v *ListOffsetsResponse) OffsetMap() (map[string]map[int32]int64, map[string]map[int32]error) {
+	offsets := make(map[string]map[int32]int64)
+	errs := make(map[string]map[int32]error)
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			var err error
+			offset := p.Offset
+			switch {
+			case p.ErrorCode != 0:
+				err = PartitionFailure{Topic: t.Topic, Partition: p.Partition, ErrorCode: p.ErrorCode}
+			case v.Version == 0 && len(p.OldStyleOffsets) == 0:
+				err = fmt.Errorf("topic %s partition %d: missing offset", t.Topic, p.Partition)
+			case v.Version == 0:
+				offset = p.OldStyleOffsets[0]
+			}
+			if err != nil {
+				if errs[t.Topic] == nil {
+					errs[t.Topic] = make(map[int32]error)
+				}
+				errs[t.Topic][p.Partition] = err
+				continue
+			}
+			if offsets[t.Topic] == nil {
+				offsets[t.Topic] = make(map[int32]int64)
+			}
+			offsets[t.Topic][p.Partition] = offset
+		}
+	}
+	return offsets, errs
+}
@@ -0,0 +1,34 @@
+package kmsg
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestListOffsetsResponseOffsetMap(t *testing.T) {
+	resp := NewPtrListOffsetsResponse()
+	resp.SetVersion(4)
+	resp.Topics = []ListOffsetsResponseTopic{{
+		Topic: "foo",
+		Partitions: []ListOffsetsResponseTopicPartition{
+			{Partition: 0, Offset: 15},
+			{Partition: 1, Offset: -1, ErrorCode: 6},
+		},
+	}}
+
+	offsets, errs := resp.OffsetMap()
+	if exp := map[string]map[int32]int64{"foo": {0: 15}}; !reflect.DeepEqual(offsets, exp) {
+		t.Errorf("got offsets %v != exp %v", offsets, exp)
+	}
+	var failure PartitionFailure
+	if len(errs) != 1 || len(errs["foo"]) != 1 || !errors.As(errs["foo"][1], &failure) || failure.ErrorCode != 6 {
+		t.Errorf("got unexpected errs %v", errs)
+	}
+
+	resp.SetVersion(0)
+	resp.Topics[0].Partitions[0].OldStyleOffsets = []int64{20, 10}
+	if offsets, _ := resp.OffsetMap(); offsets["foo"][0] != 20 {
+		t.Errorf("v0: got offset %d != exp 20", offsets["foo"][0])
+	}
+}
@@ -0,0 +1,318 @@
+package kmsg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+// Legacy message set attribute bits, used in MessageV0 and MessageV1
+// Attributes.
+const (
+	// MessageAttrCompression is the mask for the compression codec of a
+	// legacy message; see the Codec constants. Legacy messages only support
+	// gzip, snappy, and lz4.
+	MessageAttrCompression int8 = 0x03
+	// MessageAttrLogAppendTime is set if the timestamp of a v1 message is
+	// the time the broker appended the message rather than the time the
+	// producer created it.
+	MessageAttrLogAppendTime int8 = 0x08
+)
+
+// MagicOf returns the magic byte of the first batch or message in the record
+// set in, which is 0 or 1 for legacy message sets and 2 for record batches.
+// The magic byte is at a fixed offset in every format: after the int64 offset
+// and the int32 length (and, for legacy messages, the int32 CRC), at byte 16.
+// This returns false if in is too short to contain the magic byte.
+//
+// This can be used to pick ReadRecordBatches, ReadV1Messages, or
+// ReadV0Messages to read a record set.
+func MagicOf(in []byte) (int8, bool) {
+	if len(in) < 17 {
+		return 0, false
+	}
+	return int8(in[16]), true
+}
+
+// readLegacyMessages calls fn for every complete message in the message set
+// in, stopping cleanly at a truncated final message. The CRC of each message
+// is validated before calling fn.
+func readLegacyMessages(in []byte, fn func(magic int8, raw []byte) error) error {
+	for len(in) > 17 {
+		length := 12 + int(int32(binary.BigEndian.Uint32(in[8:])))
+		if length < 17 {
+			return fmt.Errorf("invalid message length %d", length-12)
+		}
+		if len(in) < length {
+			return nil // truncated final message
+		}
+		if crc := crc32.ChecksumIEEE(in[16:length]); crc != binary.BigEndian.Uint32(in[12:]) {
+			return ErrEncodedCRCMismatch
+		}
+		if err := fn(int8(in[16]), in[:length]); err != nil {
+			return err
+		}
+		in = in[length:]
+	}
+	return nil
+}
+
+// ReadV0Messages reads as many v0 messages as possible from in, which is
+// expected to be a v0 message set as found in a fetch response partition.
+// As with ReadRecordBatches, a truncated final message is discarded, and
+// ErrEncodedCRCMismatch is returned along with all messages read so far if a
+// CRC does not match. Compressed messages are returned as is; see
+// DecompressV0Messages.
+func ReadV0Messages(in []byte) ([]MessageV0, error) {
+	var msgs []MessageV0
+	err := readLegacyMessages(in, func(magic int8, raw []byte) error {
+		if magic != 0 {
+			return fmt.Errorf("unexpected message magic %d in v0 message set", magic)
+		}
+		var m MessageV0
+		if err := m.ReadFrom(raw); err != nil {
+			return err
+		}
+		msgs = append(msgs, m)
+		return nil
+	})
+	return msgs, err
+}
+
+// ReadV1Messages is the same as ReadV0Messages, but for v1 message sets.
+//
+// A v1 message set can technically contain v0 messages. These are upconverted
+// to MessageV1, keeping a Magic of 0 and using a Timestamp of -1.
+func ReadV1Messages(in []byte) ([]MessageV1, error) {
+	var msgs []MessageV1
+	err := readLegacyMessages(in, func(magic int8, raw []byte) error {
+		var m MessageV1
+		switch magic {
+		case 0:
+			var m0 MessageV0
+			if err := m0.ReadFrom(raw); err != nil {
+				return err
+			}
+			m = MessageV1{
+				Offset:      m0.Offset,
+				MessageSize: m0.MessageSize,
+				CRC:         m0.CRC,
+				Magic:       0,
+				Attributes:  m0.Attributes,
+				Timestamp:   -1,
+				Key:         m0.Key,
+				Value:       m0.Value,
+			}
+		case 1:
+			if err := m.ReadFrom(raw); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected message magic %d in v1 message set", magic)
+		}
+		msgs = append(msgs, m)
+		return nil
+	})
+	return msgs, err
+}
+
+// DecompressV0Messages returns msgs with every compressed message replaced by
+// the messages in its decompressed inner message set. The outer offset of a
+// compressed message is the offset of the last inner message, and inner
+// messages are assigned consecutive offsets ending at the outer offset.
+// Inner messages keep the compression bits of their wrapper, which is how
+// Kafka itself reports them.
+//
+// Codecs other than gzip require a registered decompressor; see
+// RegisterDecompressor.
+func DecompressV0Messages(msgs []MessageV0) ([]MessageV0, error) {
+	var out []MessageV0
+	for i := range msgs {
+		outer := &msgs[i]
+		codec := outer.Attributes & MessageAttrCompression
+		if codec == 0 {
+			out = append(out, *outer)
+			continue
+		}
+		raw, err := Decompress(codec, outer.Value)
+		if err != nil {
+			return out, fmt.Errorf("unable to decompress message at offset %d: %w", outer.Offset, err)
+		}
+		inner, err := ReadV0Messages(raw)
+		if err != nil {
+			return out, fmt.Errorf("unable to read inner messages of message at offset %d: %w", outer.Offset, err)
+		}
+		first := outer.Offset - int64(len(inner)) + 1
+		for j := range inner {
+			inner[j].Offset = first + int64(j)
+		}
+		if inner, err = DecompressV0Messages(inner); err != nil {
+			return out, err
+		}
+		for j := range inner {
+			inner[j].Attributes |= codec
+		}
+		out = append(out, inner...)
+	}
+	return out, nil
+}
+
+// DecompressV1Messages is the same as DecompressV0Messages, but for v1
+// messages. In v1 message sets, inner offsets are relative to the outer
+// message; these are resolved to absolute offsets. If the outer message uses
+// LogAppendTime, every inner message's timestamp is set to the outer
+// message's timestamp, as the broker only sets the timestamp on the outer
+// message.
+func DecompressV1Messages(msgs []MessageV1) ([]MessageV1, error) {
+	var out []MessageV1
+	for i := range msgs {
+		outer := &msgs[i]
+		codec := outer.Attributes & MessageAttrCompression
+		if codec == 0 {
+			out = append(out, *outer)
+			continue
+		}
+		raw, err := Decompress(codec, outer.Value)
+		if err != nil {
+			return out, fmt.Errorf("unable to decompress message at offset %d: %w", outer.Offset, err)
+		}
+		inner, err := ReadV1Messages(raw)
+		if err != nil {
+			return out, fmt.Errorf("unable to read inner messages of message at offset %d: %w", outer.Offset, err)
+		}
+		first := outer.Offset - int64(len(inner)) + 1
+		for j := range inner {
+			inner[j].Offset = first + int64(j)
+		}
+		if inner, err = DecompressV1Messages(inner); err != nil {
+			return out, err
+		}
+		logAppendTime := outer.Attributes&MessageAttrLogAppendTime != 0
+		for j := range inner {
+			m := &inner[j]
+			m.Attributes |= codec
+			if logAppendTime {
+				m.Attributes |= MessageAttrLogAppendTime
+				m.Timestamp = outer.Timestamp
+			}
+		}
+		out = append(out, inner...)
+	}
+	return out, nil
+}
+
+// ReadRecordSet reads every record from the record set in, regardless of the
+// format of the set: v2 record batches are read directly, while v0 and v1
+// message sets are upconverted to records. Batches and messages are
+// decompressed as necessary, and the set may mix formats (as can happen
+// around a message format upgrade). As with ReadRecordBatches, a truncated
+// final batch or message is discarded.
+//
+// Records in a set do not have a common batch to be relative to, so the
+// returned records are relative to the returned first offset and to a
+// timestamp of 0: the absolute offset of a record is firstOffset plus its
+// OffsetDelta, and the absolute timestamp of a record is its
+// TimestampDelta64. For batches and v1 messages using LogAppendTime, the
+// timestamp is the broker append time. v0 messages do not have timestamps and
+// use a timestamp of -1. Legacy message attributes do not carry over to the
+// records, and headers are only present in records from v2 batches.
+//
+// Control batches and batches belonging to aborted transactions are not
+// filtered. This returns an error if a record's offset is too far from the
+// first offset to be represented as an int32 OffsetDelta.
+func ReadRecordSet(in []byte) (firstOffset int64, records []Record, err error) {
+	add := func(offset, timestamp int64, key, value []byte, headers []Header) error {
+		if len(records) == 0 {
+			firstOffset = offset
+		}
+		delta := offset - firstOffset
+		if delta < -1<<31 || delta > 1<<31-1 {
+			return fmt.Errorf("offset %d is too far from first offset %d", offset, firstOffset)
+		}
+		r := Record{
+			TimestampDelta64: timestamp,
+			OffsetDelta:      int32(delta),
+			Key:              key,
+			Value:            value,
+			Headers:          headers,
+		}
+		r.Length = int32(r.bodySize())
+		records = append(records, r)
+		return nil
+	}
+
+	for len(in) > 17 {
+		length := 12 + int(int32(binary.BigEndian.Uint32(in[8:])))
+		if length < 17 {
+			return firstOffset, records, fmt.Errorf("invalid record set entry length %d", length-12)
+		}
+		if len(in) < length {
+			break // truncated final batch or message
+		}
+		raw := in[:length]
+		in = in[length:]
+
+		switch magic := raw[16]; magic {
+		case 0:
+			msgs, err := ReadV0Messages(raw)
+			if err == nil {
+				msgs, err = DecompressV0Messages(msgs)
+			}
+			if err != nil {
+				return firstOffset, records, err
+			}
+			for i := range msgs {
+				m := &msgs[i]
+				if err := add(m.Offset, -1, m.Key, m.Value, nil); err != nil {
+					return firstOffset, records, err
+				}
+			}
+
+		case 1:
+			msgs, err := ReadV1Messages(raw)
+			if err == nil {
+				msgs, err = DecompressV1Messages(msgs)
+			}
+			if err != nil {
+				return firstOffset, records, err
+			}
+			for i := range msgs {
+				m := &msgs[i]
+				if err := add(m.Offset, m.Timestamp, m.Key, m.Value, nil); err != nil {
+					return firstOffset, records, err
+				}
+			}
+
+		case 2:
+			if length < recordBatchOverhead {
+				return firstOffset, records, fmt.Errorf("record batch with length %d is too short", length-12)
+			}
+			bs, err := ReadRecordBatches(raw)
+			if err != nil {
+				return firstOffset, records, err
+			}
+			if len(bs) == 0 {
+				return firstOffset, records, fmt.Errorf("unable to read record batch of length %d", length-12)
+			}
+			b := &bs[0]
+			decompressed, err := Decompress(b.Codec(), b.Records)
+			if err != nil {
+				return firstOffset, records, fmt.Errorf("unable to decompress batch at offset %d: %w", b.FirstOffset, err)
+			}
+			rs, err := ReadRecords(int(b.NumRecords), decompressed)
+			if err != nil {
+				return firstOffset, records, fmt.Errorf("unable to read records in batch at offset %d: %w", b.FirstOffset, err)
+			}
+			for i := range rs {
+				r := &rs[i]
+				if err := add(b.FirstOffset+int64(r.OffsetDelta), b.recordTimestamp(r), r.Key, r.Value, r.Headers); err != nil {
+					return firstOffset, records, err
+				}
+			}
+
+		default:
+			return firstOffset, records, fmt.Errorf("unknown magic %d", magic)
+		}
+	}
+	return firstOffset, records, nil
+}
@@ -0,0 +1,198 @@
+package kmsg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"hash/crc32"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kmsg/internal/kbin"
+)
+
+// testV1Message returns a serialized v1 message with a valid size and CRC.
+func testV1Message(offset int64, attrs int8, ts int64, key, value []byte) []byte {
+	m := MessageV1{Offset: offset, Magic: 1, Attributes: attrs, Timestamp: ts, Key: key, Value: value}
+	m.MessageSize = int32(len(m.AppendTo(nil)) - 12)
+	raw := m.AppendTo(nil)
+	kbin.AppendInt32(raw[12:12], int32(crc32.ChecksumIEEE(raw[16:])))
+	return raw
+}
+
+// testV0Message returns a serialized v0 message with a valid size and CRC.
+func testV0Message(offset int64, attrs int8, key, value []byte) []byte {
+	m := MessageV0{Offset: offset, Attributes: attrs, Key: key, Value: value}
+	m.MessageSize = int32(len(m.AppendTo(nil)) - 12)
+	raw := m.AppendTo(nil)
+	kbin.AppendInt32(raw[12:12], int32(crc32.ChecksumIEEE(raw[16:])))
+	return raw
+}
+
+func testGzip(in []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(in)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDecompressV1Messages(t *testing.T) {
+	var inner []byte
+	for i := 0; i < 3; i++ {
+		inner = append(inner, testV1Message(int64(i), 0, int64(100+i), nil, []byte{'v', byte('0' + i)})...)
+	}
+
+	var in []byte
+	in = append(in, testV1Message(9, 0, 50, nil, []byte("plain"))...)
+	in = append(in, testV1Message(12, CodecGzip, 200, nil, testGzip(inner))...)
+	in = append(in, testV1Message(15, CodecGzip|MessageAttrLogAppendTime, 300, nil, testGzip(inner))...)
+	in = append(in, testV1Message(16, 0, 0, nil, nil)[:20]...) // truncated
+
+	msgs, err := ReadV1Messages(in)
+	if err != nil || len(msgs) != 3 {
+		t.Fatalf("got %d messages and err %v, expected 3 messages", len(msgs), err)
+	}
+	flat, err := DecompressV1Messages(msgs)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	exp := []struct {
+		offset int64
+		ts     int64
+		value  string
+	}{
+		{9, 50, "plain"},
+		{10, 100, "v0"},
+		{11, 101, "v1"},
+		{12, 102, "v2"},
+		{13, 300, "v0"},
+		{14, 300, "v1"},
+		{15, 300, "v2"},
+	}
+	if len(flat) != len(exp) {
+		t.Fatalf("got %d messages != exp %d", len(flat), len(exp))
+	}
+	for i, e := range exp {
+		m := flat[i]
+		if m.Offset != e.offset || m.Timestamp != e.ts || string(m.Value) != e.value {
+			t.Errorf("#%d: got offset %d ts %d value %s != exp %d %d %s", i, m.Offset, m.Timestamp, m.Value, e.offset, e.ts, e.value)
+		}
+		if i > 0 && m.Attributes&MessageAttrCompression != CodecGzip {
+			t.Errorf("#%d: inner message lost its wrapper compression: %d", i, m.Attributes)
+		}
+	}
+
+	in[len(in)-30] ^= 0xff
+	if _, err := ReadV1Messages(in); err != ErrEncodedCRCMismatch {
+		t.Errorf("got err %v, expected crc mismatch", err)
+	}
+}
+
+func TestDecompressV0Messages(t *testing.T) {
+	var inner []byte
+	for i := 0; i < 2; i++ {
+		inner = append(inner, testV0Message(int64(i), 0, nil, []byte{'v', byte('0' + i)})...)
+	}
+	msgs, err := ReadV0Messages(testV0Message(7, CodecGzip, nil, testGzip(inner)))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	flat, err := DecompressV0Messages(msgs)
+	if err != nil || len(flat) != 2 || flat[0].Offset != 6 || flat[1].Offset != 7 || string(flat[1].Value) != "v1" {
+		t.Errorf("got %v and err %v, expected offsets 6 and 7", flat, err)
+	}
+}
+
+func TestMagicOf(t *testing.T) {
+	for i, test := range []struct {
+		in    []byte
+		magic int8
+		ok    bool
+	}{
+		{testV0Message(0, 0, nil, []byte("v")), 0, true},
+		{testV1Message(0, 0, 0, nil, []byte("v")), 1, true},
+		{testBatch(0, testRecords(1)), 2, true},
+		{testBatch(0, testRecords(1))[:16], 0, false},
+		{nil, 0, false},
+	} {
+		magic, ok := MagicOf(test.in)
+		if magic != test.magic || ok != test.ok {
+			t.Errorf("#%d: got %d %v != exp %d %v", i, magic, ok, test.magic, test.ok)
+		}
+	}
+}
+
+func TestReadRecordSet(t *testing.T) {
+	values := []string{"v0", "v1", "v2"}
+
+	var v0, v1, v1Inner, v0Inner []byte
+	for i, v := range values {
+		v0Inner = append(v0Inner, testV0Message(int64(i), 0, []byte("k"), []byte(v))...)
+		v1Inner = append(v1Inner, testV1Message(int64(i), 0, int64(100+i), []byte("k"), []byte(v))...)
+	}
+	v0 = testV0Message(1<<40+2, CodecGzip, nil, testGzip(v0Inner))
+	v1 = testV1Message(1<<40+2, CodecGzip, 0, nil, testGzip(v1Inner))
+
+	rs := make([]Record, len(values))
+	for i, v := range values {
+		rs[i] = Record{TimestampDelta64: int64(i), OffsetDelta: int32(i), Key: []byte("k"), Value: []byte(v)}
+		rs[i].Length = int32(rs[i].bodySize())
+	}
+	b := NewRecordBatchBuilder()
+	b.FirstTimestamp = 100
+	for _, r := range rs {
+		b.Add(r)
+	}
+	batch, err := b.Build(CodecGzip)
+	if err != nil {
+		t.Fatalf("unable to build batch: %v", err)
+	}
+	batch.FirstOffset = 1 << 40
+	v2 := batch.AppendTo(nil)
+
+	for _, test := range []struct {
+		name string
+		in   []byte
+		ts   func(i int) int64
+	}{
+		{"v0", v0, func(int) int64 { return -1 }},
+		{"v1", v1, func(i int) int64 { return int64(100 + i) }},
+		{"v2", v2, func(i int) int64 { return int64(100 + i) }},
+	} {
+		first, got, err := ReadRecordSet(test.in)
+		if err != nil {
+			t.Fatalf("%s: unexpected err: %v", test.name, err)
+		}
+		if first != 1<<40 || len(got) != len(values) {
+			t.Fatalf("%s: got first offset %d and %d records != exp %d and %d", test.name, first, len(got), int64(1<<40), len(values))
+		}
+		for i, r := range got {
+			if r.OffsetDelta != int32(i) || r.TimestampDelta64 != test.ts(i) || string(r.Key) != "k" || string(r.Value) != values[i] {
+				t.Errorf("%s #%d: got unexpected record %v", test.name, i, r)
+			}
+			if r.Length != int32(r.bodySize()) {
+				t.Errorf("%s #%d: got length %d != exp %d", test.name, i, r.Length, r.bodySize())
+			}
+		}
+	}
+
+	// Mixed formats, as seen around a message format upgrade.
+	batch.FirstOffset = 1<<40 + 3
+	mixed := append(append([]byte(nil), v1...), batch.AppendTo(nil)...)
+	first, got, err := ReadRecordSet(mixed)
+	if err != nil || first != 1<<40 || len(got) != 6 {
+		t.Fatalf("mixed: got first offset %d, %d records, err %v; expected %d, 6, no error", first, len(got), err, int64(1<<40))
+	}
+	for i, r := range got {
+		if r.OffsetDelta != int32(i) {
+			t.Errorf("mixed #%d: got offset delta %d != exp %d", i, r.OffsetDelta, i)
+		}
+	}
+
+	// A v2 entry too short to be a batch, followed by more data, must error
+	// rather than panic.
+	short := append(kbin.AppendInt32(make([]byte, 8), 5), 0, 0, 0, 0, 2)
+	if _, _, err := ReadRecordSet(append(short, make([]byte, 30)...)); err == nil {
+		t.Error("expected an error for a too short v2 batch")
+	}
+}
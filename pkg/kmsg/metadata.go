@@ -0,0 +1,214 @@
+package kmsg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitFetchByLeader splits req into one request per broker, where each
+// request contains the topics and partitions in req that the broker leads
+// according to md. The returned map is keyed by broker ID. Each request is a
+// copy of req with the same top level fields, minus the fetch session: a
+// session (KIP-227) is specific to one broker, so each request has its
+// SessionID reset to 0 and SessionEpoch reset to -1 to fetch without a
+// session, and ForgottenTopics is cleared.
+//
+// Topics are matched by name for requests prior to v13, and by topic ID for
+// v13+. Partitions that are in the metadata but currently have no leader are
+// placed in a request under the broker ID -1. Partitions that are not in the
+// metadata at all are not placed in any request, and are instead returned in
+// an error along with the requests that could be split.
+func SplitFetchByLeader(req *FetchRequest, md *MetadataResponse) (map[int32]*FetchRequest, error) {
+	byName := make(map[string]*MetadataResponseTopic, len(md.Topics))
+	byID := make(map[[16]byte]*MetadataResponseTopic, len(md.Topics))
+	for i := range md.Topics {
+		t := &md.Topics[i]
+		if t.Topic != nil {
+			byName[*t.Topic] = t
+		}
+		if t.TopicID != ([16]byte{}) {
+			byID[t.TopicID] = t
+		}
+	}
+
+	var (
+		split   = make(map[int32]*FetchRequest)
+		lastIdx = make(map[int32]int) // broker => index in req.Topics of the last topic added
+		missing []string
+	)
+	for i := range req.Topics {
+		rt := &req.Topics[i]
+		var mt *MetadataResponseTopic
+		if req.Version >= 13 {
+			mt = byID[rt.TopicID]
+		} else {
+			mt = byName[rt.Topic]
+		}
+		leaders := make(map[int32]int32)
+		if mt != nil {
+			for j := range mt.Partitions {
+				leaders[mt.Partitions[j].Partition] = mt.Partitions[j].Leader
+			}
+		}
+
+		var missingPartitions []string
+		for _, rp := range rt.Partitions {
+			leader, ok := leaders[rp.Partition]
+			if !ok {
+				missingPartitions = append(missingPartitions, fmt.Sprint(rp.Partition))
+				continue
+			}
+			if leader < 0 {
+				leader = -1
+			}
+			sub := split[leader]
+			if sub == nil {
+				c := *req
+				c.Topics = nil
+				c.SessionID = 0
+				c.SessionEpoch = -1
+				c.ForgottenTopics = nil
+				sub = &c
+				split[leader] = sub
+			}
+			if idx, ok := lastIdx[leader]; !ok || idx != i {
+				st := *rt
+				st.Partitions = nil
+				sub.Topics = append(sub.Topics, st)
+				lastIdx[leader] = i
+			}
+			st := &sub.Topics[len(sub.Topics)-1]
+			st.Partitions = append(st.Partitions, rp)
+		}
+		if len(missingPartitions) > 0 {
+			name := rt.Topic
+			if req.Version >= 13 {
+				name = fmt.Sprintf("%x", rt.TopicID)
+			}
+			missing = append(missing, fmt.Sprintf("%s[%s]", name, strings.Join(missingPartitions, " ")))
+		}
+	}
+	if len(missing) > 0 {
+		return split, fmt.Errorf("unable to split fetch: partitions not in metadata: %s", strings.Join(missing, ", "))
+	}
+	return split, nil
+}
+
+// MetadataAllTopics returns a metadata request for all topics in the cluster.
+// The request's Topics are nil, which is serialized as null in v1+ (meaning
+// all topics) and as an empty array in v0 (which also means all topics).
+func MetadataAllTopics() *MetadataRequest {
+	return NewPtrMetadataRequest()
+}
+
+// MetadataNoTopics returns a metadata request for no topics, which returns
+// only the brokers and controller of the cluster. The request's Topics are
+// empty but non-nil, which is serialized as an empty array.
+//
+// Metadata v0 cannot request no topics: an empty array in v0 means all
+// topics. This request must be issued at v1+ to request no topics.
+func MetadataNoTopics() *MetadataRequest {
+	req := NewPtrMetadataRequest()
+	req.Topics = []MetadataRequestTopic{}
+	return req
+}
+
+// MetadataForTopics returns a metadata request for the given topics. If no
+// topics are given, this is the same as MetadataNoTopics (not all topics).
+func MetadataForTopics(topics ...string) *MetadataRequest {
+	req := MetadataNoTopics()
+	for _, topic := range topics {
+		t := NewMetadataRequestTopic()
+		t.Topic = StringPtr(topic)
+		req.Topics = append(req.Topics, t)
+	}
+	return req
+}
+
+// CoalesceMetadata returns a new request for the union of the topics in a and
+// b, with all other fields copied from a. If either request is for all topics
+// (null Topics), the returned request is for all topics. Topics are
+// deduplicated by name, or by topic ID for topics without a name, and are
+// kept in the order they first appear in a and then b.
+//
+// This should only be used if CanCoalesce(a, b) is true.
+func CoalesceMetadata(a, b *MetadataRequest) *MetadataRequest {
+	merged := *a
+	merged.Topics = nil
+	if a.Topics == nil || b.Topics == nil {
+		return &merged
+	}
+	merged.Topics = make([]MetadataRequestTopic, 0, len(a.Topics)+len(b.Topics))
+	var (
+		names = make(map[string]bool)
+		ids   = make(map[[16]byte]bool)
+	)
+	for _, topics := range [][]MetadataRequestTopic{a.Topics, b.Topics} {
+		for _, t := range topics {
+			if t.Topic != nil {
+				if names[*t.Topic] {
+					continue
+				}
+				names[*t.Topic] = true
+			} else {
+				if ids[t.TopicID] {
+					continue
+				}
+				ids[t.TopicID] = true
+			}
+			merged.Topics = append(merged.Topics, t)
+		}
+	}
+	return &merged
+}
+
+// TopicByID returns the topic in the response with the given topic ID, or
+// false if no topic has the ID. Topic IDs are returned in v10+ responses; the
+// zero ID never matches, since it is what brokers return for topics without
+// an ID.
+//
+// Topic IDs are modeled as [16]byte throughout this package, which is the
+// UUID encoding Kafka uses on the wire.
+func (v *MetadataResponse) TopicByID(id [16]byte) (*MetadataResponseTopic, bool) {
+	if id == ([16]byte{}) {
+		return nil, false
+	}
+	for i := range v.Topics {
+		if t := &v.Topics[i]; t.TopicID == id {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// LeaderNotAvailable returns whether the given topic in the response is
+// waiting for partition leaders to be elected: the topic or any of its
+// partitions has the error LEADER_NOT_AVAILABLE (5), or any of its partitions
+// has no leader. This is the transient state of a topic that was just
+// created, including a topic that was auto created by this metadata request
+// (AllowAutoTopicCreation), and means the client should retry the metadata
+// request shortly.
+//
+// Metadata responses do not indicate whether a topic was auto created, but a
+// topic that did not exist before the request and is in this state was almost
+// certainly created by it. This returns false if the topic is not in the
+// response.
+func (v *MetadataResponse) LeaderNotAvailable(topic string) bool {
+	const leaderNotAvailable = 5
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		if t.Topic == nil || *t.Topic != topic {
+			continue
+		}
+		if t.ErrorCode == leaderNotAvailable {
+			return true
+		}
+		for j := range t.Partitions {
+			if p := &t.Partitions[j]; p.ErrorCode == leaderNotAvailable || p.Leader < 0 {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
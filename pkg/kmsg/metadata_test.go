@@ -0,0 +1,234 @@
+package kmsg
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// testMetadata returns a metadata response for the given topics, each with
+// one partition per leader in order.
+func testMetadata(topics map[string][]int32) *MetadataResponse {
+	md := NewPtrMetadataResponse()
+	for topic, leaders := range topics {
+		t := NewMetadataResponseTopic()
+		t.Topic = StringPtr(topic)
+		t.TopicID[0] = topic[0]
+		for i, leader := range leaders {
+			p := NewMetadataResponseTopicPartition()
+			p.Partition = int32(i)
+			p.Leader = leader
+			t.Partitions = append(t.Partitions, p)
+		}
+		md.Topics = append(md.Topics, t)
+	}
+	return md
+}
+
+func TestSplitFetchByLeader(t *testing.T) {
+	md := testMetadata(map[string][]int32{
+		"foo": {1, 2, 1},
+		"bar": {2, -1},
+	})
+
+	req := NewPtrFetchRequest()
+	req.Version = 12
+	req.MaxWaitMillis = 500
+	req.SessionID = 3
+	req.SessionEpoch = 4
+	req.ForgottenTopics = []FetchRequestForgottenTopic{{Topic: "baz"}}
+	for _, tp := range []struct {
+		topic      string
+		partitions []int32
+	}{
+		{"foo", []int32{0, 1, 2}},
+		{"bar", []int32{0, 1, 5}},
+	} {
+		rt := NewFetchRequestTopic()
+		rt.Topic = tp.topic
+		for _, p := range tp.partitions {
+			rp := NewFetchRequestTopicPartition()
+			rp.Partition = p
+			rp.FetchOffset = int64(p * 10)
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		req.Topics = append(req.Topics, rt)
+	}
+
+	split, err := SplitFetchByLeader(req, md)
+	if err == nil {
+		t.Error("expected error for partition missing from metadata")
+	}
+
+	topicPartitions := func(r *FetchRequest) map[string][]int32 {
+		m := make(map[string][]int32)
+		for _, t := range r.Topics {
+			for _, p := range t.Partitions {
+				m[t.Topic] = append(m[t.Topic], p.Partition)
+			}
+		}
+		return m
+	}
+	exp := map[int32]map[string][]int32{
+		1:  {"foo": {0, 2}},
+		2:  {"foo": {1}, "bar": {0}},
+		-1: {"bar": {1}},
+	}
+	if len(split) != len(exp) {
+		t.Fatalf("got %d requests != exp %d", len(split), len(exp))
+	}
+	for broker, tps := range exp {
+		sub := split[broker]
+		if sub == nil {
+			t.Errorf("missing request for broker %d", broker)
+			continue
+		}
+		if got := topicPartitions(sub); !reflect.DeepEqual(got, tps) {
+			t.Errorf("broker %d: got %v != exp %v", broker, got, tps)
+		}
+		if sub.Version != 12 || sub.MaxWaitMillis != 500 || sub.SessionID != 0 || sub.SessionEpoch != -1 || sub.ForgottenTopics != nil {
+			t.Errorf("broker %d: top level fields not copied or session not reset as expected: %v", broker, sub)
+		}
+	}
+	if got := split[1].Topics[0].Partitions[1].FetchOffset; got != 20 {
+		t.Errorf("got fetch offset %d != exp 20", got)
+	}
+
+	// v13+ matches by topic ID.
+	req.Version = 13
+	for i := range req.Topics {
+		req.Topics[i].TopicID[0] = req.Topics[i].Topic[0]
+		req.Topics[i].Topic = ""
+	}
+	req.Topics[1].Partitions = req.Topics[1].Partitions[:2]
+	split, err = SplitFetchByLeader(req, md)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(split) != 3 || len(split[2].Topics) != 2 {
+		t.Errorf("got unexpected v13 split %v", split)
+	}
+}
+
+func TestMetadataTopicsBuilders(t *testing.T) {
+	topicsLen := func(req *MetadataRequest, version int16) int32 {
+		req.Version = version
+		return int32(binary.BigEndian.Uint32(req.AppendTo(nil)))
+	}
+	for _, test := range []struct {
+		name  string
+		req   *MetadataRequest
+		nilOk bool
+		v0    int32
+		v1    int32
+	}{
+		{"all", MetadataAllTopics(), true, 0, -1},
+		{"none", MetadataNoTopics(), false, 0, 0},
+		{"none via for", MetadataForTopics(), false, 0, 0},
+		{"two", MetadataForTopics("foo", "bar"), false, 2, 2},
+	} {
+		if (test.req.Topics == nil) != test.nilOk {
+			t.Errorf("%s: got nil topics %v != exp %v", test.name, test.req.Topics == nil, test.nilOk)
+		}
+		if got := topicsLen(test.req, 0); got != test.v0 {
+			t.Errorf("%s: got v0 topics length %d != exp %d", test.name, got, test.v0)
+		}
+		if got := topicsLen(test.req, 4); got != test.v1 {
+			t.Errorf("%s: got v4 topics length %d != exp %d", test.name, got, test.v1)
+		}
+	}
+	if req := MetadataForTopics("foo"); *req.Topics[0].Topic != "foo" {
+		t.Errorf("got topic %s != exp foo", *req.Topics[0].Topic)
+	}
+}
+
+func TestCoalesceMetadata(t *testing.T) {
+	a := MetadataForTopics("foo", "bar")
+	b := MetadataForTopics("bar", "baz")
+	if !CanCoalesce(a, b) {
+		t.Fatal("unexpectedly cannot coalesce metadata requests")
+	}
+
+	merged := CoalesceMetadata(a, b)
+	var got []string
+	for _, t := range merged.Topics {
+		got = append(got, *t.Topic)
+	}
+	if exp := []string{"foo", "bar", "baz"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got topics %v != exp %v", got, exp)
+	}
+	if len(a.Topics) != 2 || len(b.Topics) != 2 {
+		t.Error("coalescing unexpectedly modified the inputs")
+	}
+
+	if merged := CoalesceMetadata(a, MetadataAllTopics()); merged.Topics != nil {
+		t.Errorf("got topics %v when coalescing with all topics, expected nil", merged.Topics)
+	}
+
+	b.AllowAutoTopicCreation = !a.AllowAutoTopicCreation
+	if CanCoalesce(a, b) {
+		t.Error("unexpectedly can coalesce requests with different auto topic creation")
+	}
+	b.AllowAutoTopicCreation = a.AllowAutoTopicCreation
+	b.SetVersion(a.GetVersion() - 1)
+	if CanCoalesce(a, b) {
+		t.Error("unexpectedly can coalesce requests at different versions")
+	}
+	if CanCoalesce(NewPtrFetchRequest(), NewPtrFetchRequest()) {
+		t.Error("unexpectedly can coalesce fetch requests")
+	}
+}
+
+func TestMetadataTopicByID(t *testing.T) {
+	md := testMetadata(map[string][]int32{"foo": {1}, "bar": {2}})
+	md.SetVersion(12)
+	for i := range md.Topics {
+		md.Topics[i].TopicID[0] = byte(i + 1)
+	}
+	id := md.Topics[1].TopicID
+	name := *md.Topics[1].Topic
+
+	got := NewPtrMetadataResponse()
+	got.SetVersion(12)
+	if err := got.ReadFrom(md.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read response: %v", err)
+	}
+	if topic, ok := got.TopicByID(id); !ok || topic.Topic == nil || *topic.Topic != name {
+		t.Errorf("got topic %v (ok? %v), expected %s", topic, ok, name)
+	}
+	if _, ok := got.TopicByID([16]byte{9}); ok {
+		t.Error("unexpectedly found unknown topic ID")
+	}
+	if _, ok := got.TopicByID([16]byte{}); ok {
+		t.Error("unexpectedly found zero topic ID")
+	}
+}
+
+func TestMetadataLeaderNotAvailable(t *testing.T) {
+	md := testMetadata(map[string][]int32{
+		"ready":      {1, 2},
+		"no leader":  {1, -1},
+		"created":    nil,
+		"partitions": {1},
+	})
+	for i := range md.Topics {
+		switch *md.Topics[i].Topic {
+		case "created":
+			md.Topics[i].ErrorCode = 5
+		case "partitions":
+			md.Topics[i].Partitions[0].ErrorCode = 5
+		}
+	}
+
+	for topic, exp := range map[string]bool{
+		"ready":      false,
+		"no leader":  true,
+		"created":    true,
+		"partitions": true,
+		"missing":    false,
+	} {
+		if got := md.LeaderNotAvailable(topic); got != exp {
+			t.Errorf("%s: got %v != exp %v", topic, got, exp)
+		}
+	}
+}
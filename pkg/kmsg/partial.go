@@ -0,0 +1,31 @@
+package kmsg
+
+import "fmt"
+
+// ReadResponsePartial decodes body, which must not include the response
+// header, into a response for the given key at the given version. Unlike
+// ReadFrom, this returns the response even if decoding fails, populated as
+// far as decoding got, along with the names of the top level fields that
+// were successfully read (in wire order). This is useful to debug exactly
+// where a truncated or malformed response was cut off.
+//
+// The returned response is nil only if the key is unknown. Fields in the
+// response that were not fully read are left at their default or are only
+// partially populated, e.g., an array may have its length set but only some
+// of its elements read.
+func ReadResponsePartial(key, version int16, body []byte) (Response, []string, error) {
+	resp := ResponseForKey(key)
+	if resp == nil {
+		return nil, nil, fmt.Errorf("unknown response key %d", key)
+	}
+	resp.SetVersion(version)
+	pr, ok := resp.(interface {
+		readFrom([]byte, bool, *[]string) error
+	})
+	if !ok {
+		return resp, nil, resp.ReadFrom(body)
+	}
+	var read []string
+	err := pr.readFrom(body, false, &read)
+	return resp, read, err
+}
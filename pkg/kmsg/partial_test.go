@@ -0,0 +1,45 @@
+package kmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadResponsePartial(t *testing.T) {
+	resp := NewPtrFindCoordinatorResponse()
+	resp.Version = 2
+	resp.ThrottleMillis = 100
+	resp.ErrorMessage = StringPtr("msg")
+	resp.NodeID = 3
+	resp.Host = "localhost"
+	resp.Port = 9092
+	full := resp.AppendTo(nil)
+
+	got, read, err := ReadResponsePartial(resp.Key(), resp.Version, full)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if exp := []string{"ThrottleMillis", "ErrorCode", "ErrorMessage", "NodeID", "Host", "Port"}; !reflect.DeepEqual(read, exp) {
+		t.Errorf("full: got read %v != exp %v", read, exp)
+	}
+	if !reflect.DeepEqual(got, resp) {
+		t.Errorf("full: got %v != exp %v", got, resp)
+	}
+
+	// Truncate within the host string.
+	got, read, err = ReadResponsePartial(resp.Key(), resp.Version, full[:len(full)-8])
+	if err == nil {
+		t.Fatal("expected error decoding truncated response")
+	}
+	if exp := []string{"ThrottleMillis", "ErrorCode", "ErrorMessage", "NodeID"}; !reflect.DeepEqual(read, exp) {
+		t.Errorf("truncated: got read %v != exp %v", read, exp)
+	}
+	partial := got.(*FindCoordinatorResponse)
+	if partial.ThrottleMillis != 100 || partial.NodeID != 3 || partial.Host != "" {
+		t.Errorf("truncated: got unexpected partial response %v", partial)
+	}
+
+	if _, _, err := ReadResponsePartial(-1, 0, nil); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
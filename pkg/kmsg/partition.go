@@ -0,0 +1,111 @@
+package kmsg
+
+import "sync/atomic"
+
+// Partition returns the partition for key out of numPartitions, exactly as
+// Kafka's default partitioner does for keyed records: the murmur2 hash of the
+// key with the high bit cleared, modulo numPartitions. Producers that use
+// this to partition keyed records place each key on the same partition as
+// the Java client, which is necessary to co-partition topics with Java
+// producers.
+//
+// A nil key is hashed as an empty key. Kafka does not hash records with null
+// keys; see RoundRobinPartition. This returns -1 if numPartitions is not
+// positive.
+func Partition(key []byte, numPartitions int32) int32 {
+	if numPartitions <= 0 {
+		return -1
+	}
+	// Kafka converts the hash to an int and strips the sign bit with
+	// toPositive; masking before or after the conversion is equivalent.
+	return int32(murmur2(key)&0x7fffffff) % numPartitions
+}
+
+var roundRobinCounter uint32
+
+// RoundRobinPartition returns the next partition out of numPartitions in a
+// process wide round robin, which is one way to spread records that have null
+// keys. Successive calls cycle through the partitions, regardless of topic.
+// This is safe for concurrent use, and returns -1 if numPartitions is not
+// positive.
+func RoundRobinPartition(numPartitions int32) int32 {
+	if numPartitions <= 0 {
+		return -1
+	}
+	n := atomic.AddUint32(&roundRobinCounter, 1) - 1
+	return int32(n % uint32(numPartitions))
+}
+
+// PartitionForRecord returns the partition for r out of numPartitions as a
+// default partitioner would: a record with a key is partitioned with
+// Partition, exactly matching the Java client's
+// toPositive(murmur2(key)) % numPartitions, while a record with a null key is
+// partitioned with RoundRobinPartition. An empty but non-null key is hashed.
+//
+// The Java client's default partitioner uses a sticky partition for records
+// with null keys, which only differs in which partition each record lands on.
+// This returns -1 if numPartitions is not positive.
+func PartitionForRecord(r *Record, numPartitions int32) int32 {
+	if r.Key == nil {
+		return RoundRobinPartition(numPartitions)
+	}
+	return Partition(r.Key, numPartitions)
+}
+
+// PartitionRecords groups recs by the partition out of numPartitions that
+// each record is partitioned to with PartitionForRecord, preserving the order
+// of records within each partition. Each group can be built into a batch with
+// BuildBatchWithRecords or a RecordBatchBuilder. Records with keys group
+// deterministically, while records with null keys are spread round robin.
+//
+// This returns nil if numPartitions is not positive.
+func PartitionRecords(recs []Record, numPartitions int32) map[int32][]Record {
+	if numPartitions <= 0 {
+		return nil
+	}
+	partitioned := make(map[int32][]Record)
+	for i := range recs {
+		p := PartitionForRecord(&recs[i], numPartitions)
+		partitioned[p] = append(partitioned[p], recs[i])
+	}
+	return partitioned
+}
+
+// murmur2 is the murmur2 hash as implemented by Kafka's Java client.
+// https://github.com/apache/kafka/blob/d91a94e/clients/src/main/java/org/apache/kafka/common/utils/Utils.java#L383-L421
+//
+// The Java code uses ints but with unsigned shifts; we do not need to.
+func murmur2(b []byte) uint32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    uint32 = 0x5bd1e995
+		r           = 24
+	)
+	h := seed ^ uint32(len(b))
+	for len(b) >= 4 {
+		k := uint32(b[3])<<24 + uint32(b[2])<<16 + uint32(b[1])<<8 + uint32(b[0])
+		b = b[4:]
+		k *= m
+		k ^= k >> r
+		k *= m
+
+		h *= m
+		h ^= k
+	}
+	switch len(b) {
+	case 3:
+		h ^= uint32(b[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(b[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(b[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
+}
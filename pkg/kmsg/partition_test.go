@@ -0,0 +1,139 @@
+package kmsg
+
+import "testing"
+
+func TestMurmur2(t *testing.T) {
+	// Vectors from Kafka's UtilsTest.testMurmur2.
+	for _, test := range []struct {
+		key string
+		exp int32
+	}{
+		{"21", -973932308},
+		{"foobar", -790332482},
+		{"a-little-bit-long-string", -985981536},
+		{"a-little-bit-longer-string", -1486304829},
+		{"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", -58897971},
+		{"abc", 479470107},
+	} {
+		if got := int32(murmur2([]byte(test.key))); got != test.exp {
+			t.Errorf("%q: got %d != exp %d", test.key, got, test.exp)
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	for _, test := range []struct {
+		key string
+		n   int32
+		exp int32
+	}{
+		// toPositive(-973932308) = 1173551340
+		{"21", 10, 0},
+		{"21", 7, 1173551340 % 7},
+		// toPositive(-790332482) = 1357151166
+		{"foobar", 12, 1357151166 % 12},
+		{"abc", 3, 479470107 % 3},
+		{"abc", 1, 0},
+	} {
+		if got := Partition([]byte(test.key), test.n); got != test.exp {
+			t.Errorf("%q %d: got %d != exp %d", test.key, test.n, got, test.exp)
+		}
+	}
+	if got := Partition([]byte("abc"), 0); got != -1 {
+		t.Errorf("got %d for no partitions != exp -1", got)
+	}
+}
+
+func TestRoundRobinPartition(t *testing.T) {
+	seen := make(map[int32]bool)
+	for i := 0; i < 3; i++ {
+		p := RoundRobinPartition(3)
+		if p < 0 || p >= 3 {
+			t.Fatalf("got out of range partition %d", p)
+		}
+		seen[p] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("got partitions %v, expected all three", seen)
+	}
+}
+
+func TestPartitionForRecord(t *testing.T) {
+	keyed := &Record{Key: []byte("foobar")}
+	if got := PartitionForRecord(keyed, 12); got != 1357151166%12 {
+		t.Errorf("keyed: got %d != exp %d", got, 1357151166%12)
+	}
+	if got, exp := PartitionForRecord(&Record{Key: []byte{}}, 12), Partition(nil, 12); got != exp {
+		t.Errorf("empty key: got %d != exp %d", got, exp)
+	}
+
+	seen := make(map[int32]bool)
+	for i := 0; i < 4; i++ {
+		p := PartitionForRecord(&Record{Value: []byte("v")}, 4)
+		if p < 0 || p >= 4 {
+			t.Fatalf("null key: got out of range partition %d", p)
+		}
+		seen[p] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("null key: got partitions %v, expected all four", seen)
+	}
+}
+
+func TestPartitionRecords(t *testing.T) {
+	var recs []Record
+	for _, key := range []string{"21", "foobar", "21", "abc"} {
+		recs = append(recs, Record{Key: []byte(key), Value: []byte(key)})
+	}
+	for i := 0; i < 3; i++ {
+		recs = append(recs, Record{Value: []byte{byte(i)}})
+	}
+
+	partitioned := PartitionRecords(recs, 3)
+	var total, nullKeys int
+	for p, rs := range partitioned {
+		total += len(rs)
+		for _, r := range rs {
+			if r.Key == nil {
+				nullKeys++
+				continue
+			}
+			if exp := Partition(r.Key, 3); p != exp {
+				t.Errorf("key %q: in partition %d != exp %d", r.Key, p, exp)
+			}
+		}
+	}
+	if total != len(recs) {
+		t.Errorf("got %d records != exp %d", total, len(recs))
+	}
+	if nullKeys != 3 {
+		t.Errorf("got %d null key records != exp 3", nullKeys)
+	}
+
+	// Both "21" records land on the same partition, in order.
+	p21 := Partition([]byte("21"), 3)
+	var got []string
+	for _, r := range partitioned[p21] {
+		if r.Key != nil && string(r.Key) == "21" {
+			got = append(got, string(r.Value))
+		}
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d records for key 21 on partition %d != exp 2", len(got), p21)
+	}
+
+	// Null key records spread across all partitions.
+	spread := make(map[int32]bool)
+	for p, rs := range PartitionRecords(recs[4:], 3) {
+		if len(rs) > 0 {
+			spread[p] = true
+		}
+	}
+	if len(spread) != 3 {
+		t.Errorf("null key records spread to %v, expected all three partitions", spread)
+	}
+
+	if PartitionRecords(recs, 0) != nil {
+		t.Error("expected nil for no partitions")
+	}
+}
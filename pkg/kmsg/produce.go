@@ -0,0 +1,210 @@
+package kmsg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Acks values for ProduceRequest.Acks. Any other value is rejected by the
+// broker with INVALID_REQUIRED_ACKS.
+const (
+	// AcksNone has the broker not reply to the produce request at all.
+	AcksNone int16 = 0
+	// AcksLeader has the broker reply once the leader has written the
+	// records to its local log.
+	AcksLeader int16 = 1
+	// AcksAll has the broker reply once all in-sync replicas have the
+	// records.
+	AcksAll int16 = -1
+)
+
+// ValidAcks returns whether a is one of AcksNone, AcksLeader, or AcksAll.
+func ValidAcks(a int16) bool {
+	switch a {
+	case AcksNone, AcksLeader, AcksAll:
+		return true
+	}
+	return false
+}
+
+// SetAcks sets the request's acks, returning an error if the acks are not
+// valid. The request is unmodified on error.
+func (v *ProduceRequest) SetAcks(acks int16) error {
+	if !ValidAcks(acks) {
+		return fmt.Errorf("invalid acks %d", acks)
+	}
+	v.Acks = acks
+	return nil
+}
+
+// BuildProduce returns a produce request with the given acks and timeout
+// containing one record batch per partition in data, which is a map of
+// topics to partitions to the records to produce. Each batch is built with a
+// RecordBatchBuilder, meaning all derived fields and the CRC are set, and is
+// compressed with codec. Topics and partitions are sorted in the request so
+// that the request is deterministic.
+//
+// Partitions with no records are skipped, as brokers reject empty batches,
+// and a topic with no records in any partition is skipped entirely.
+//
+// The batches are not idempotent; to produce idempotently, build the batches
+// with a RecordBatchBuilder directly.
+func BuildProduce(acks int16, timeoutMs int32, data map[string]map[int32][]Record, codec int8) (*ProduceRequest, error) {
+	req := NewPtrProduceRequest()
+	req.Acks = acks
+	req.TimeoutMillis = timeoutMs
+
+	topics := make([]string, 0, len(data))
+	for topic := range data {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	b := NewRecordBatchBuilder()
+	for _, topic := range topics {
+		partitions := data[topic]
+		rt := NewProduceRequestTopic()
+		rt.Topic = topic
+		for partition, recs := range partitions {
+			if len(recs) == 0 {
+				continue
+			}
+			rp := NewProduceRequestTopicPartition()
+			rp.Partition = partition
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		sort.Slice(rt.Partitions, func(i, j int) bool { return rt.Partitions[i].Partition < rt.Partitions[j].Partition })
+
+		for i := range rt.Partitions {
+			rp := &rt.Partitions[i]
+			b.Reset()
+			for _, r := range partitions[rp.Partition] {
+				b.Add(r)
+			}
+			batch, err := b.Build(codec)
+			if err != nil {
+				return nil, fmt.Errorf("topic %s partition %d: %w", topic, rp.Partition, err)
+			}
+			rp.Records = batch.AppendTo(nil)
+		}
+		if len(rt.Partitions) > 0 {
+			req.Topics = append(req.Topics, rt)
+		}
+	}
+	return req, nil
+}
+
+// BaseOffsets returns the base offset assigned to each successfully produced
+// partition in the response, as well as the error code for each partition
+// that failed. Each error code can be converted to an error with
+// kerr.ErrorForCode. A partition is in exactly one of the two maps.
+func (v *ProduceResponse) BaseOffsets() (offsets map[string]map[int32]int64, errCodes map[string]map[int32]int16) {
+	offsets = make(map[string]map[int32]int64)
+	errCodes = make(map[string]map[int32]int16)
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			if p.ErrorCode != 0 {
+				if errCodes[t.Topic] == nil {
+					errCodes[t.Topic] = make(map[int32]int16)
+				}
+				errCodes[t.Topic][p.Partition] = p.ErrorCode
+				continue
+			}
+			if offsets[t.Topic] == nil {
+				offsets[t.Topic] = make(map[int32]int64)
+			}
+			offsets[t.Topic][p.Partition] = p.BaseOffset
+		}
+	}
+	return offsets, errCodes
+}
+
+// LogAppendTimes returns the LogAppendTime of each successfully produced
+// partition whose topic uses LogAppendTime timestamps. The LogAppendTime is
+// the millisecond timestamp the broker assigned to every record in the
+// produced batch. Responses prior to v2 do not contain a LogAppendTime, and
+// topics that use CreateTime timestamps return -1; neither are included.
+func (v *ProduceResponse) LogAppendTimes() map[string]map[int32]int64 {
+	times := make(map[string]map[int32]int64)
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			if v.Version < 2 || p.ErrorCode != 0 || p.LogAppendTime == -1 {
+				continue
+			}
+			if times[t.Topic] == nil {
+				times[t.Topic] = make(map[int32]int64)
+			}
+			times[t.Topic][p.Partition] = p.LogAppendTime
+		}
+	}
+	return times
+}
+
+// RecordLocation is the location of a record or batch in a ProduceRequest.
+type RecordLocation struct {
+	Topic     string
+	Partition int32
+	// Batch is the index of the batch within the partition's records.
+	Batch int
+	// Record is the index of the record within the batch, or -1 if the
+	// location refers to the batch as a whole.
+	Record int
+	// Size is the size of the record, or of the whole batch if Record is
+	// -1.
+	Size int
+}
+
+// OversizedRecords returns the location of every batch and every record in
+// the request that is larger than maxBytes, which is usually the topic's
+// max.message.bytes (or the broker's message.max.bytes). The broker rejects a
+// partition with MESSAGE_TOO_LARGE if a batch is larger than the limit, which
+// is always the case if a single record is larger than the limit. Checking
+// before producing allows a producer to reject or split the records.
+//
+// The size of a batch is its serialized size, which for compressed batches is
+// the compressed size. The size of a record is its uncompressed serialized
+// size, meaning a record can be reported while its compressed batch is not.
+// Only v2 record batches are inspected. This returns an error if any batch
+// cannot be read or decompressed.
+func (v *ProduceRequest) OversizedRecords(maxBytes int) ([]RecordLocation, error) {
+	var (
+		oversized []RecordLocation
+		batches   []RecordBatch
+		records   []Record
+		err       error
+	)
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			batches, err = ReadRecordBatchesInto(batches, p.Records)
+			if err != nil {
+				return oversized, fmt.Errorf("topic %s partition %d: %w", t.Topic, p.Partition, err)
+			}
+			for k := range batches {
+				b := &batches[k]
+				if size := 12 + int(b.Length); size > maxBytes {
+					oversized = append(oversized, RecordLocation{t.Topic, p.Partition, k, -1, size})
+				}
+				raw, err := Decompress(b.Codec(), b.Records)
+				if err != nil {
+					return oversized, fmt.Errorf("topic %s partition %d: unable to decompress batch %d: %w", t.Topic, p.Partition, k, err)
+				}
+				records, err = ReadRecordsInto(records, int(b.NumRecords), raw)
+				if err != nil {
+					return oversized, fmt.Errorf("topic %s partition %d: unable to read records in batch %d: %w", t.Topic, p.Partition, k, err)
+				}
+				for l := range records {
+					if size := records[l].Size(); size > maxBytes {
+						oversized = append(oversized, RecordLocation{t.Topic, p.Partition, k, l, size})
+					}
+				}
+			}
+		}
+	}
+	return oversized, nil
+}
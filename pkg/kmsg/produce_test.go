@@ -0,0 +1,180 @@
+package kmsg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestBuildProduce(t *testing.T) {
+	data := map[string]map[int32][]Record{
+		"foo": {
+			1: {{Key: []byte("k0"), Value: []byte("v0")}, {Key: []byte("k1"), Value: []byte("v1"), TimestampDelta64: 5}},
+			0: {{Value: []byte("v2")}},
+		},
+		"bar": {
+			3: {{Key: []byte("k3"), Headers: []Header{{Key: "h", Value: []byte("hv")}}}},
+			4: nil, // empty partitions are skipped
+		},
+		"baz": {0: {}}, // as are topics with no records
+	}
+
+	for _, codec := range []int8{CodecNone, CodecGzip} {
+		req, err := BuildProduce(-1, 5000, data, codec)
+		if err != nil {
+			t.Fatalf("codec %d: unexpected err: %v", codec, err)
+		}
+		if req.Acks != -1 || req.TimeoutMillis != 5000 {
+			t.Errorf("codec %d: got acks %d timeout %d != exp -1 5000", codec, req.Acks, req.TimeoutMillis)
+		}
+
+		// Round trip the request itself, then read every batch back out.
+		req.Version = 9
+		got := ProduceRequest{Version: req.Version}
+		if err := got.ReadFrom(req.AppendTo(nil)); err != nil {
+			t.Fatalf("codec %d: unable to read request: %v", codec, err)
+		}
+		if len(got.Topics) != 2 || got.Topics[0].Topic != "bar" || got.Topics[1].Topic != "foo" {
+			t.Fatalf("codec %d: got unexpected topics %v", codec, got.Topics)
+		}
+		if len(got.Topics[0].Partitions) != 1 {
+			t.Errorf("codec %d: got bar partitions %v, expected only the non-empty partition", codec, got.Topics[0].Partitions)
+		}
+		for _, rt := range got.Topics {
+			for i, rp := range rt.Partitions {
+				if i > 0 && rt.Partitions[i-1].Partition >= rp.Partition {
+					t.Errorf("codec %d: partitions not sorted: %v", codec, rt.Partitions)
+				}
+				bs, err := ReadRecordBatches(rp.Records)
+				if err != nil || len(bs) != 1 {
+					t.Fatalf("codec %d: got %d batches and err %v, expected 1 batch", codec, len(bs), err)
+				}
+				b := bs[0]
+				if b.Codec() != codec {
+					t.Errorf("codec %d: got batch codec %d", codec, b.Codec())
+				}
+				raw, err := Decompress(b.Codec(), b.Records)
+				if err != nil {
+					t.Fatalf("codec %d: unable to decompress: %v", codec, err)
+				}
+				rs, err := ReadRecords(int(b.NumRecords), raw)
+				if err != nil {
+					t.Fatalf("codec %d: unable to read records: %v", codec, err)
+				}
+				exp := data[rt.Topic][rp.Partition]
+				if len(rs) != len(exp) || int(b.LastOffsetDelta) != len(exp)-1 {
+					t.Fatalf("codec %d: got %d records (last delta %d) != exp %d", codec, len(rs), b.LastOffsetDelta, len(exp))
+				}
+				for j := range rs {
+					if rs[j].OffsetDelta != int32(j) ||
+						!reflect.DeepEqual(rs[j].Key, exp[j].Key) ||
+						!reflect.DeepEqual(rs[j].Value, exp[j].Value) ||
+						!reflect.DeepEqual(rs[j].Headers, exp[j].Headers) {
+						t.Errorf("codec %d: got record %v != exp %v", codec, rs[j], exp[j])
+					}
+				}
+				if rt.Topic == "foo" && rp.Partition == 1 && b.MaxTimestamp != 5 {
+					t.Errorf("codec %d: got max timestamp %d != exp 5", codec, b.MaxTimestamp)
+				}
+			}
+		}
+	}
+
+	if _, err := BuildProduce(1, 1000, data, CodecZstd); err == nil {
+		t.Error("expected error building with an unregistered compressor")
+	}
+}
+
+func TestProduceResponseBaseOffsets(t *testing.T) {
+	resp := NewPtrProduceResponse()
+	resp.Version = 8
+	topic := NewProduceResponseTopic()
+	topic.Topic = "foo"
+	ok := NewProduceResponseTopicPartition()
+	ok.Partition = 0
+	ok.BaseOffset = 100
+	ok.LogAppendTime = 1234
+	failed := NewProduceResponseTopicPartition()
+	failed.Partition = 1
+	failed.ErrorCode = 6 // NOT_LEADER_FOR_PARTITION
+	failed.BaseOffset = -1
+	createTime := NewProduceResponseTopicPartition()
+	createTime.Partition = 2
+	createTime.BaseOffset = 5
+	createTime.LogAppendTime = -1
+	topic.Partitions = append(topic.Partitions, ok, failed, createTime)
+	resp.Topics = append(resp.Topics, topic)
+
+	offsets, errCodes := resp.BaseOffsets()
+	if exp := map[string]map[int32]int64{"foo": {0: 100, 2: 5}}; !reflect.DeepEqual(offsets, exp) {
+		t.Errorf("got offsets %v != exp %v", offsets, exp)
+	}
+	if exp := map[string]map[int32]int16{"foo": {1: 6}}; !reflect.DeepEqual(errCodes, exp) {
+		t.Errorf("got error codes %v != exp %v", errCodes, exp)
+	}
+	if got, exp := resp.LogAppendTimes(), map[string]map[int32]int64{"foo": {0: 1234}}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got log append times %v != exp %v", got, exp)
+	}
+
+	resp.Version = 1
+	if got := resp.LogAppendTimes(); len(got) != 0 {
+		t.Errorf("got log append times %v for v1, expected none", got)
+	}
+}
+
+func TestValidAcks(t *testing.T) {
+	for _, test := range []struct {
+		acks  int16
+		valid bool
+	}{
+		{AcksNone, true},
+		{AcksLeader, true},
+		{AcksAll, true},
+		{2, false},
+		{-2, false},
+	} {
+		if got := ValidAcks(test.acks); got != test.valid {
+			t.Errorf("acks %d: got valid %v != exp %v", test.acks, got, test.valid)
+		}
+		req := NewPtrProduceRequest()
+		req.Acks = 7
+		err := req.SetAcks(test.acks)
+		if test.valid && (err != nil || req.Acks != test.acks) {
+			t.Errorf("acks %d: got err %v and acks %d, expected no error", test.acks, err, req.Acks)
+		}
+		if !test.valid && (err == nil || req.Acks != 7) {
+			t.Errorf("acks %d: got err %v and acks %d, expected error and unmodified acks", test.acks, err, req.Acks)
+		}
+	}
+}
+
+func TestProduceOversizedRecords(t *testing.T) {
+	big := bytes.Repeat([]byte("x"), 1000)
+	req, err := BuildProduce(AcksAll, 1000, map[string]map[int32][]Record{
+		"foo": {
+			0: {{Value: []byte("small")}, {Value: big}},
+			1: {{Value: []byte("small")}},
+		},
+	}, CodecNone)
+	if err != nil {
+		t.Fatalf("unable to build: %v", err)
+	}
+
+	got, err := req.OversizedRecords(500)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d oversized locations != exp 2: %v", len(got), got)
+	}
+	if b := got[0]; b.Topic != "foo" || b.Partition != 0 || b.Batch != 0 || b.Record != -1 || b.Size <= 1000 {
+		t.Errorf("got unexpected batch location %v", b)
+	}
+	if r := got[1]; r.Topic != "foo" || r.Partition != 0 || r.Batch != 0 || r.Record != 1 || r.Size <= 1000 {
+		t.Errorf("got unexpected record location %v", r)
+	}
+
+	if got, err := req.OversizedRecords(2000); err != nil || len(got) != 0 {
+		t.Errorf("got %v and err %v with a large limit, expected nothing", got, err)
+	}
+}
@@ -9,7 +9,11 @@ type Record struct {
 	// follows this field. It is an int32 encoded as a varint.
 	Length int32
 
-	// Attributes are record level attributes. This field currently is unused.
+	// Attributes are record level attributes. Kafka does not currently
+	// use any record level attribute bits; all bits are reserved. This is
+	// distinct from a RecordBatch's Attributes (see the RecordBatchAttr
+	// constants), which apply to every record in the batch. Any value here
+	// is serialized and deserialized as is for forward compatibility.
 	Attributes int8
 
 	// TimestampDelta is the millisecond delta of this record's timestamp
@@ -87,6 +91,47 @@ func (v *Record) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// Size returns the number of bytes this record serializes to, exactly as
+// AppendTo would serialize it (i.e., len(v.AppendTo(nil))). The size
+// includes the varint Length prefix, which is sized from the Length field as
+// is; Length must be set correctly for a record to be valid, in which case
+// Size is the record's size within a RecordBatch.
+func (v *Record) Size() int {
+	n := kbin.VarintLen(v.Length)
+	return n + v.bodySize()
+}
+
+// bodySize returns the size of everything following the Length field.
+func (v *Record) bodySize() int {
+	n := 1 // attributes
+	{
+		d := v.TimestampDelta64
+		if d == 0 {
+			d = int64(v.TimestampDelta)
+		}
+		n += kbin.VarlongLen(d)
+	}
+	n += kbin.VarintLen(v.OffsetDelta)
+	n += varintBytesLen(v.Key)
+	n += varintBytesLen(v.Value)
+	n += kbin.VarintLen(int32(len(v.Headers)))
+	for i := range v.Headers {
+		h := &v.Headers[i]
+		n += kbin.VarintLen(int32(len(h.Key))) + len(h.Key)
+		n += varintBytesLen(h.Value)
+	}
+	return n
+}
+
+// varintBytesLen returns the size of b when encoded with AppendVarintBytes,
+// where a nil slice is encoded as a varint -1.
+func varintBytesLen(b []byte) int {
+	if b == nil {
+		return kbin.VarintLen(-1)
+	}
+	return kbin.VarintLen(int32(len(b))) + len(b)
+}
+
 func (v *Record) ReadFrom(src []byte) error {
 	return v.readFrom(src, false)
 }
@@ -0,0 +1,407 @@
+package kmsg
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/twmb/franz-go/pkg/kmsg/internal/kbin"
+)
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrEncodedCRCMismatch is returned from reading record batches if a batch's
+// encoded CRC does not match the CRC calculated from the batch.
+var ErrEncodedCRCMismatch = errors.New("encoded crc does not match calculated crc")
+
+// ErrVarintOverflow is returned when a varint, such as the length prefix of a
+// record, has too many continuation bytes or a value that overflows the type
+// being decoded.
+var ErrVarintOverflow = kbin.ErrVarintOverflow
+
+// ErrNotEnoughData is returned when a type could not fully decode from a
+// slice because the slice did not have enough data.
+var ErrNotEnoughData = kbin.ErrNotEnoughData
+
+// ErrShort is returned when input is too short to decode and the number of
+// bytes needed is known. It is ErrNotEnoughData under errors.Is.
+type ErrShort = kbin.ErrShort
+
+// RecordBatch attribute bits. These apply to a batch as a whole and are
+// distinct from a Record's Attributes, which are per record and reserved.
+const (
+	// RecordBatchAttrCompression is the mask for the compression codec of
+	// a batch; see the Codec constants.
+	RecordBatchAttrCompression int16 = 0x0007
+	// RecordBatchAttrLogAppendTime is set if the timestamps in a batch are
+	// the time the broker appended the batch (LogAppendTime), rather than
+	// the time the producer created the records (CreateTime).
+	RecordBatchAttrLogAppendTime int16 = 0x0008
+	// RecordBatchAttrTransactional is set if a batch is part of a
+	// transaction.
+	RecordBatchAttrTransactional int16 = 0x0010
+	// RecordBatchAttrControl is set if a batch contains a control record,
+	// which is written by the broker to mark transaction boundaries.
+	RecordBatchAttrControl int16 = 0x0020
+	// RecordBatchAttrDeleteHorizon is set if a batch's FirstTimestamp is
+	// the delete horizon of the batch rather than the timestamp of the
+	// first record (KIP-534).
+	RecordBatchAttrDeleteHorizon int16 = 0x0040
+)
+
+// Codec returns the compression codec of the batch.
+func (v *RecordBatch) Codec() int8 {
+	return int8(v.Attributes & RecordBatchAttrCompression)
+}
+
+// IsTransactional returns whether the batch is part of a transaction.
+func (v *RecordBatch) IsTransactional() bool {
+	return v.Attributes&RecordBatchAttrTransactional != 0
+}
+
+// IsControl returns whether the batch is a control batch.
+func (v *RecordBatch) IsControl() bool {
+	return v.Attributes&RecordBatchAttrControl != 0
+}
+
+// HasLogAppendTime returns whether the batch timestamps are LogAppendTime
+// rather than CreateTime.
+func (v *RecordBatch) HasLogAppendTime() bool {
+	return v.Attributes&RecordBatchAttrLogAppendTime != 0
+}
+
+// HasDeleteHorizon returns whether the batch's FirstTimestamp is the delete
+// horizon set by the broker during compaction (KIP-534), rather than the
+// timestamp of the first record.
+func (v *RecordBatch) HasDeleteHorizon() bool {
+	return v.Attributes&RecordBatchAttrDeleteHorizon != 0
+}
+
+// CompressionRatio returns the size of the batch's compressed records divided
+// by the size of the records once decompressed, which is less than 1 if
+// compression shrunk the records. This returns 1 for an uncompressed batch or
+// a batch with no records. Decompressing uses the registered decompressor for
+// the batch's codec; see RegisterDecompressor.
+func (v *RecordBatch) CompressionRatio() (float64, error) {
+	codec := v.Codec()
+	if codec == CodecNone {
+		return 1, nil
+	}
+	raw, err := Decompress(codec, v.Records)
+	if err != nil {
+		return 0, err
+	}
+	if len(raw) == 0 {
+		return 1, nil
+	}
+	return float64(len(v.Records)) / float64(len(raw)), nil
+}
+
+// ProducerState returns the producer ID, producer epoch, first sequence
+// number, and whether the batch is transactional. These are the fields needed
+// to track idempotent or transactional producer state per batch.
+func (v *RecordBatch) ProducerState() (id int64, epoch int16, baseSeq int32, transactional bool) {
+	return v.ProducerID, v.ProducerEpoch, v.FirstSequence, v.IsTransactional()
+}
+
+// NextOffset returns the offset to fetch after consuming the batch, which is
+// the offset following the last offset in the batch: FirstOffset plus
+// LastOffsetDelta plus one. This uses LastOffsetDelta rather than the offset
+// of the last record, since compaction can remove the last records of a batch
+// while the batch retains its original LastOffsetDelta.
+//
+// Control batches (see IsControl) occupy offsets as well, and a consumer must
+// still advance past them with NextOffset even though it does not return
+// their records to the user.
+func (v *RecordBatch) NextOffset() int64 {
+	return v.FirstOffset + int64(v.LastOffsetDelta) + 1
+}
+
+// recordTimestamp returns the absolute timestamp of r, a record in the batch:
+// the batch's MaxTimestamp if the batch uses LogAppendTime, or otherwise the
+// batch's FirstTimestamp plus the record's timestamp delta.
+func (v *RecordBatch) recordTimestamp(r *Record) int64 {
+	if v.HasLogAppendTime() {
+		return v.MaxTimestamp
+	}
+	d := r.TimestampDelta64
+	if d == 0 {
+		d = int64(r.TimestampDelta)
+	}
+	return v.FirstTimestamp + d
+}
+
+// ReadBatchLeaderEpoch returns the PartitionLeaderEpoch of the serialized
+// record batch in without decoding the batch. The leader epoch is at a fixed
+// offset in the batch header, after the int64 first offset and the int32
+// length. This returns a *ErrShort if in is too short to contain the leader
+// epoch and magic, or an error if the magic is not 2: legacy v0 and v1
+// messages do not have a leader epoch.
+func ReadBatchLeaderEpoch(in []byte) (int32, error) {
+	if len(in) < 17 {
+		return -1, &ErrShort{Need: 17, Have: len(in)}
+	}
+	if magic := in[16]; magic != 2 {
+		return -1, fmt.Errorf("unknown record batch magic %d", magic)
+	}
+	return int32(binary.BigEndian.Uint32(in[12:])), nil
+}
+
+// ReadRecordBatches reads as many record batches as possible from in, which
+// is expected to be a record set as found in a produce request or a fetch
+// response partition.
+//
+// Kafka can return a partial batch at the end of a fetch response; if the
+// final batch is truncated, it is discarded and reading stops cleanly. A
+// batch that has all of its bytes present but that cannot be decoded (for
+// example, its encoded length is too small to hold a batch header) is corrupt
+// rather than truncated, and an error is returned along with all batches read
+// so far. Each batch's CRC is validated, and ErrEncodedCRCMismatch is returned
+// along with all batches read so far if a CRC does not match.
+//
+// The Records field of each returned batch aliases in.
+func ReadRecordBatches(in []byte) ([]RecordBatch, error) {
+	return ReadRecordBatchesInto(nil, in)
+}
+
+// ReadRecordBatchesInto is the same as ReadRecordBatches, but reads into dst
+// rather than allocating a new slice, and returns the updated dst.
+//
+// The length of dst is reset to zero and its capacity is reused, and any
+// batch already within the capacity of dst is overwritten. Thus, any batches
+// from a prior call must not be used after calling this function again with
+// the same slice. As with ReadRecordBatches, the Records field of each batch
+// aliases in, meaning in must not be modified while the batches are in use.
+func ReadRecordBatchesInto(dst []RecordBatch, in []byte) ([]RecordBatch, error) {
+	return readRecordBatchesInto(dst, in, readCfg{})
+}
+
+// ReadOpt is an option to configure ReadRecordBatchesOpts.
+type ReadOpt interface {
+	apply(*readCfg)
+}
+
+type readCfg struct {
+	skipCRC bool
+}
+
+type readOpt struct{ fn func(*readCfg) }
+
+func (opt readOpt) apply(cfg *readCfg) { opt.fn(cfg) }
+
+// SkipCRCCheck skips validating the CRC of each batch, meaning a batch with
+// a stale or corrupt CRC is returned rather than failing with
+// ErrEncodedCRCMismatch. This can be used to read from a source that is known
+// to write batches with incorrect CRCs but otherwise valid data; it should
+// not be used otherwise, since it also skips detecting real corruption.
+func SkipCRCCheck() ReadOpt {
+	return readOpt{func(cfg *readCfg) { cfg.skipCRC = true }}
+}
+
+// ReadRecordBatchesOpts is the same as ReadRecordBatches, but with options
+// applied. With no options, this is exactly ReadRecordBatches.
+func ReadRecordBatchesOpts(in []byte, opts ...ReadOpt) ([]RecordBatch, error) {
+	var cfg readCfg
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	return readRecordBatchesInto(nil, in, cfg)
+}
+
+func readRecordBatchesInto(dst []RecordBatch, in []byte, cfg readCfg) ([]RecordBatch, error) {
+	dst = dst[:0]
+	for len(in) > 17 {
+		length := int(int32(binary.BigEndian.Uint32(in[8:])))
+		length += 12 // for the int64 first offset and the int32 length field itself
+		if length < 12 {
+			return dst, fmt.Errorf("invalid negative record batch length %d", length-12)
+		}
+		if len(in) < length {
+			break // truncated final batch
+		}
+		if magic := in[16]; magic != 2 {
+			return dst, fmt.Errorf("unknown record batch magic %d", magic)
+		}
+
+		if len(dst) < cap(dst) {
+			dst = dst[:len(dst)+1]
+		} else {
+			dst = append(dst, RecordBatch{})
+		}
+		b := &dst[len(dst)-1]
+		if err := b.ReadFrom(in[:length]); err != nil {
+			// We have all bytes for this batch, meaning it is
+			// corrupt rather than truncated.
+			return dst[:len(dst)-1], fmt.Errorf("invalid record batch at offset %d with length %d: %w", b.FirstOffset, length-12, err)
+		}
+		if !cfg.skipCRC {
+			if crc := int32(crc32.Checksum(in[21:length], crc32c)); crc != b.CRC {
+				return dst[:len(dst)-1], ErrEncodedCRCMismatch
+			}
+		}
+		in = in[length:]
+	}
+	return dst, nil
+}
+
+// lastRecordSetOffset returns the offset of the last record in the last
+// complete batch or legacy message in the record set in, or false if there is
+// no complete batch. A v2 batch's last offset is its first offset plus its
+// LastOffsetDelta, which directly follows the attributes at offset 21. A
+// legacy message's offset is the offset of the message itself, or for a
+// compressed wrapper message, the offset of the last inner message.
+func lastRecordSetOffset(in []byte) (int64, bool, error) {
+	var (
+		last int64
+		ok   bool
+	)
+	for len(in) > 17 {
+		length := 12 + int(int32(binary.BigEndian.Uint32(in[8:])))
+		if length < 12 {
+			return 0, false, fmt.Errorf("invalid negative record batch length %d", length-12)
+		}
+		if len(in) < length {
+			break // truncated final batch
+		}
+		offset := int64(binary.BigEndian.Uint64(in))
+		switch magic := in[16]; magic {
+		case 0, 1:
+			last = offset
+		case 2:
+			if length < 27 {
+				return 0, false, fmt.Errorf("record batch at offset %d with length %d is too short", offset, length-12)
+			}
+			last = offset + int64(int32(binary.BigEndian.Uint32(in[23:])))
+		default:
+			return 0, false, fmt.Errorf("unknown record batch magic %d", magic)
+		}
+		ok = true
+		in = in[length:]
+	}
+	return last, ok, nil
+}
+
+// verifyRecordSetCRCs verifies the CRC of every complete batch or legacy
+// message in the record set in without otherwise decoding it, stopping at a
+// truncated final batch. v2 batches use a Castagnoli CRC over everything
+// following the CRC at offset 17, while legacy v0 and v1 messages use an IEEE
+// CRC over everything following the CRC at offset 12.
+func verifyRecordSetCRCs(in []byte) error {
+	for len(in) > 17 {
+		length := 12 + int(int32(binary.BigEndian.Uint32(in[8:])))
+		if length < 12 {
+			return fmt.Errorf("invalid negative record batch length %d", length-12)
+		}
+		if len(in) < length {
+			return nil // truncated final batch
+		}
+		offset := int64(binary.BigEndian.Uint64(in))
+		switch magic := in[16]; magic {
+		case 0, 1:
+			// CRC, magic, attributes, and the key and value lengths.
+			if length < 26 {
+				return fmt.Errorf("message at offset %d with size %d is too short", offset, length-12)
+			}
+			if crc := crc32.ChecksumIEEE(in[16:length]); crc != binary.BigEndian.Uint32(in[12:]) {
+				return fmt.Errorf("message at offset %d: %w", offset, ErrEncodedCRCMismatch)
+			}
+		case 2:
+			if length < 21 {
+				return fmt.Errorf("record batch at offset %d with length %d is too short", offset, length-12)
+			}
+			if crc := crc32.Checksum(in[21:length], crc32c); crc != binary.BigEndian.Uint32(in[17:]) {
+				return fmt.Errorf("record batch at offset %d: %w", offset, ErrEncodedCRCMismatch)
+			}
+		default:
+			return fmt.Errorf("unknown record batch magic %d", magic)
+		}
+		in = in[length:]
+	}
+	return nil
+}
+
+// ReadRecords reads n records from in, which is expected to be the
+// uncompressed Records field of a RecordBatch. If fewer than n records could
+// be read, this returns the records read so far along with an error. A record
+// length that is a malformed varint is reported as ErrVarintOverflow,
+// rather than being treated as a truncated record. A truncated record is
+// reported as a *ErrShort, which is ErrNotEnoughData under errors.Is and which
+// contains the number of bytes needed to read the record.
+func ReadRecords(n int, in []byte) ([]Record, error) {
+	return ReadRecordsInto(nil, n, in)
+}
+
+// ReadRecordsInto is the same as ReadRecords, but reads into dst rather than
+// allocating a new slice, and returns the updated dst.
+//
+// The length of dst is reset to zero and its capacity is reused. Records
+// within the capacity of dst are overwritten, and their Headers slices are
+// reused as well. Thus, any records from a prior call must not be used after
+// calling this function again with the same slice. The Key and Value of each
+// record, as well as the Value of each header, alias in.
+func ReadRecordsInto(dst []Record, n int, in []byte) ([]Record, error) {
+	dst = dst[:0]
+	for i := 0; i < n; i++ {
+		b := kbin.Reader{Src: in}
+		length, err := b.VarintChecked()
+		if err != nil {
+			return dst, err
+		}
+		total := len(in) - len(b.Src) + int(length)
+		if length < 0 {
+			return dst, ErrNotEnoughData
+		}
+		if len(in) < total {
+			return dst, &ErrShort{Need: total, Have: len(in)}
+		}
+		if len(dst) < cap(dst) {
+			dst = dst[:len(dst)+1]
+		} else {
+			dst = append(dst, Record{})
+		}
+		if err := (&dst[len(dst)-1]).ReadFrom(in[:total]); err != nil {
+			return dst[:len(dst)-1], err
+		}
+		in = in[total:]
+	}
+	return dst, nil
+}
+
+// ReadRecordsPartial reads as many complete records from in as possible,
+// returning the records and the unconsumed tail of in. Unlike ReadRecords,
+// this does not require knowing the number of records: it reads until in is
+// exhausted or only a partial record remains, which is useful when reading
+// from a buffer that may have been truncated mid-record. A non-empty leftover
+// means a partial record remains.
+//
+// This returns an error only if a record is malformed: a negative length, an
+// overflowing length varint, or a record that does not decode within its
+// length. As with ReadRecords, the Key and Value of each record, as well as
+// the Value of each header, alias in.
+func ReadRecordsPartial(in []byte) (recs []Record, leftover []byte, err error) {
+	for len(in) > 0 {
+		b := kbin.Reader{Src: in}
+		length, err := b.VarintChecked()
+		if err != nil {
+			if errors.Is(err, kbin.ErrNotEnoughData) {
+				break
+			}
+			return recs, in, err
+		}
+		if length < 0 {
+			return recs, in, fmt.Errorf("invalid negative record length %d", length)
+		}
+		total := len(in) - len(b.Src) + int(length)
+		if len(in) < total {
+			break
+		}
+		var r Record
+		if err := r.ReadFrom(in[:total]); err != nil {
+			return recs, in, err
+		}
+		recs = append(recs, r)
+		in = in[total:]
+	}
+	return recs, in, nil
+}
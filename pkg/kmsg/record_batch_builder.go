@@ -0,0 +1,274 @@
+package kmsg
+
+import "hash/crc32"
+
+// RecordBatchBuilder builds a v2 RecordBatch from individual records,
+// calculating every derived field of the batch (offset deltas, lengths,
+// timestamps, the record count, and the CRC) and compressing the records.
+//
+// The zero value is not usable; use NewRecordBatchBuilder.
+type RecordBatchBuilder struct {
+	// FirstTimestamp is the timestamp, in milliseconds, that each added
+	// record's TimestampDelta64 is relative to.
+	FirstTimestamp int64
+
+	// ProducerID and ProducerEpoch are the producer ID and epoch to use for
+	// an idempotent or transactional batch. These are -1 by default, which
+	// is what a non-idempotent batch uses.
+	ProducerID    int64
+	ProducerEpoch int16
+
+	// FirstSequence is the producer sequence number of the first record in
+	// an idempotent or transactional batch, and is -1 by default. Records do
+	// not carry their own sequence numbers: the order of records within the
+	// batch defines their sequence, i.e., the record at OffsetDelta n has
+	// sequence FirstSequence+n. Thus, for each partition, the FirstSequence
+	// of a batch must be the FirstSequence of the prior batch plus the number
+	// of records in the prior batch; see NextSequence.
+	FirstSequence int32
+
+	// Transactional, if true, marks the batch as part of a transaction.
+	Transactional bool
+
+	records []Record
+}
+
+// NewRecordBatchBuilder returns a new builder for a non-idempotent batch.
+func NewRecordBatchBuilder() *RecordBatchBuilder {
+	return &RecordBatchBuilder{
+		ProducerID:    -1,
+		ProducerEpoch: -1,
+		FirstSequence: -1,
+	}
+}
+
+// Add adds a record to the batch. The record's OffsetDelta is set to its
+// index in the batch and its Length is calculated; the record's
+// TimestampDelta64 (or TimestampDelta, if TimestampDelta64 is zero) is used
+// as is.
+func (b *RecordBatchBuilder) Add(r Record) {
+	r.OffsetDelta = int32(len(b.records))
+	r.Length = int32(r.bodySize())
+	b.records = append(b.records, r)
+}
+
+// NumRecords returns the number of records added to the batch.
+func (b *RecordBatchBuilder) NumRecords() int {
+	return len(b.records)
+}
+
+// recordBatchOverhead is the size of a v2 record batch header, which is
+// everything in a serialized batch before the records.
+const recordBatchOverhead = 61
+
+// EstimatedSize returns the size of the batch as serialized if it were built
+// with CodecNone: the fixed batch header plus the size of every added record.
+// Compression usually shrinks the records, making this an upper bound on the
+// size of a compressed batch that is cheap to compute, although compressing
+// small or incompressible records can add a few bytes of overhead.
+func (b *RecordBatchBuilder) EstimatedSize() int {
+	n := recordBatchOverhead
+	for i := range b.records {
+		n += b.records[i].Size()
+	}
+	return n
+}
+
+// NextSequence returns the FirstSequence to use for the batch following the
+// batch currently being built, that is, FirstSequence plus the number of
+// added records. Sequence numbers wrap from the max int32 back to 0. If
+// FirstSequence is negative (the batch is not idempotent), this returns -1.
+func (b *RecordBatchBuilder) NextSequence() int32 {
+	if b.FirstSequence < 0 {
+		return -1
+	}
+	next := int64(b.FirstSequence) + int64(len(b.records))
+	if next > 1<<31-1 {
+		next -= 1 << 31
+	}
+	return int32(next)
+}
+
+// Reset removes all records from the builder, retaining the other fields.
+func (b *RecordBatchBuilder) Reset() {
+	b.records = b.records[:0]
+}
+
+// Build returns a batch containing all added records, compressed with the
+// given codec. The returned batch has its FirstOffset set to 0, as is used
+// for producing, and its CRC is valid for the batch as serialized with
+// AppendTo.
+//
+// Codecs other than CodecNone and CodecGzip require a registered compressor;
+// see RegisterCompressor.
+func (b *RecordBatchBuilder) Build(codec int8) (RecordBatch, error) {
+	batch := newProduceBatch(codec, b.ProducerID, b.ProducerEpoch, b.FirstSequence, b.Transactional)
+	batch.LastOffsetDelta = int32(len(b.records) - 1)
+	batch.FirstTimestamp = b.FirstTimestamp
+	batch.MaxTimestamp = b.FirstTimestamp
+	batch.NumRecords = int32(len(b.records))
+
+	var raw []byte
+	for i := range b.records {
+		r := &b.records[i]
+		d := r.TimestampDelta64
+		if d == 0 {
+			d = int64(r.TimestampDelta)
+		}
+		if ts := b.FirstTimestamp + d; ts > batch.MaxTimestamp {
+			batch.MaxTimestamp = ts
+		}
+		raw = r.AppendTo(raw)
+	}
+	err := finishProduceBatch(&batch, codec, raw)
+	return batch, err
+}
+
+// BuildBatchWithRecords returns a valid, uncompressed batch with the given
+// FirstOffset containing recs, with every derived field and the CRC set. This
+// is meant for building test data with explicit offsets, such as a compacted
+// batch with gaps between offsets: unlike RecordBatchBuilder, which assigns
+// each record the next offset, each record's OffsetDelta is used as is, and
+// the batch's LastOffsetDelta is the largest OffsetDelta in recs.
+//
+// Each record's Length is calculated, while its TimestampDelta64 is used as
+// is relative to a FirstTimestamp of 0. The batch is not idempotent. recs is
+// not modified.
+func BuildBatchWithRecords(baseOffset int64, recs []Record) *RecordBatch {
+	batch := newProduceBatch(CodecNone, -1, -1, -1, false)
+	batch.FirstOffset = baseOffset
+	batch.NumRecords = int32(len(recs))
+
+	var raw []byte
+	for i, r := range recs {
+		r.Length = int32(r.bodySize())
+		if i == 0 || r.OffsetDelta > batch.LastOffsetDelta {
+			batch.LastOffsetDelta = r.OffsetDelta
+		}
+		if r.TimestampDelta64 > batch.MaxTimestamp {
+			batch.MaxTimestamp = r.TimestampDelta64
+		}
+		raw = r.AppendTo(raw)
+	}
+	finishProduceBatch(&batch, CodecNone, raw) // uncompressed never fails
+	return &batch
+}
+
+// newProduceBatch returns a v2 batch for producing with every field set but
+// the record and timestamp fields, the length, and the CRC.
+func newProduceBatch(codec int8, producerID int64, producerEpoch int16, firstSequence int32, transactional bool) RecordBatch {
+	batch := NewRecordBatch()
+	batch.PartitionLeaderEpoch = -1
+	batch.Magic = 2
+	batch.Attributes = int16(codec) & RecordBatchAttrCompression
+	if transactional {
+		batch.Attributes |= RecordBatchAttrTransactional
+	}
+	batch.ProducerID = producerID
+	batch.ProducerEpoch = producerEpoch
+	batch.FirstSequence = firstSequence
+	return batch
+}
+
+// finishProduceBatch compresses the serialized records raw into the batch
+// and sets the batch's length and CRC.
+func finishProduceBatch(batch *RecordBatch, codec int8, raw []byte) error {
+	compressed, err := Compress(codec, raw)
+	if err != nil {
+		return err
+	}
+	batch.Records = compressed
+	batch.Length = int32(49 + len(batch.Records))
+	batch.CRC = int32(crc32.Checksum(batch.AppendTo(nil)[21:], crc32c))
+	return nil
+}
+
+// RecordBatchWriter incrementally builds a v2 record batch by serializing
+// each record as it is added, rather than holding every Record until the
+// batch is built as RecordBatchBuilder does. This is useful for producers
+// that accumulate many records before flushing.
+//
+// The zero value is not usable; use NewRecordBatchWriter.
+type RecordBatchWriter struct {
+	// ProducerID, ProducerEpoch, FirstSequence, and Transactional are the
+	// same as in RecordBatchBuilder, and are -1, -1, -1, and false by
+	// default.
+	ProducerID    int64
+	ProducerEpoch int16
+	FirstSequence int32
+	Transactional bool
+
+	raw        []byte
+	numRecords int32
+	firstTs    int64
+	maxTs      int64
+}
+
+// NewRecordBatchWriter returns a new writer for a non-idempotent batch.
+func NewRecordBatchWriter() *RecordBatchWriter {
+	return &RecordBatchWriter{
+		ProducerID:    -1,
+		ProducerEpoch: -1,
+		FirstSequence: -1,
+	}
+}
+
+// AddRecord serializes a record with the given key, value, headers, and
+// millisecond timestamp into the batch. The first added record's timestamp is
+// the batch's FirstTimestamp, and the timestamps of all other records are
+// stored relative to it. The headers are serialized immediately and can be
+// reused after this returns.
+func (w *RecordBatchWriter) AddRecord(key, value []byte, headers []Header, timestamp int64) {
+	if w.numRecords == 0 {
+		w.firstTs = timestamp
+		w.maxTs = timestamp
+	} else if timestamp > w.maxTs {
+		w.maxTs = timestamp
+	}
+	r := Record{
+		TimestampDelta64: timestamp - w.firstTs,
+		OffsetDelta:      w.numRecords,
+		Key:              key,
+		Value:            value,
+		Headers:          headers,
+	}
+	r.Length = int32(r.bodySize())
+	w.raw = r.AppendTo(w.raw)
+	w.numRecords++
+}
+
+// NumRecords returns the number of records added to the batch.
+func (w *RecordBatchWriter) NumRecords() int {
+	return int(w.numRecords)
+}
+
+// BufferedBytes returns the size of the serialized, uncompressed records
+// added to the batch.
+func (w *RecordBatchWriter) BufferedBytes() int {
+	return len(w.raw)
+}
+
+// Reset removes all records from the writer, retaining its buffer and the
+// producer fields.
+func (w *RecordBatchWriter) Reset() {
+	w.raw = w.raw[:0]
+	w.numRecords = 0
+}
+
+// Finish returns the serialized batch containing every added record,
+// compressed with the given codec, with all derived fields and the CRC set.
+// The writer can be reused after calling Reset.
+//
+// Codecs other than CodecNone and CodecGzip require a registered compressor;
+// see RegisterCompressor.
+func (w *RecordBatchWriter) Finish(codec int8) ([]byte, error) {
+	batch := newProduceBatch(codec, w.ProducerID, w.ProducerEpoch, w.FirstSequence, w.Transactional)
+	batch.LastOffsetDelta = w.numRecords - 1
+	batch.FirstTimestamp = w.firstTs
+	batch.MaxTimestamp = w.maxTs
+	batch.NumRecords = w.numRecords
+	if err := finishProduceBatch(&batch, codec, w.raw); err != nil {
+		return nil, err
+	}
+	return batch.AppendTo(nil), nil
+}
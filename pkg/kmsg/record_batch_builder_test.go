@@ -0,0 +1,161 @@
+package kmsg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRecordBatchBuilderSequence(t *testing.T) {
+	b := NewRecordBatchBuilder()
+	b.ProducerID = 10
+	b.ProducerEpoch = 1
+	b.FirstSequence = 0
+
+	var expSeq int32
+	for i, n := range []int{3, 1, 4} {
+		b.Reset()
+		for j := 0; j < n; j++ {
+			b.Add(Record{Value: []byte("v")})
+		}
+		batch, err := b.Build(CodecNone)
+		if err != nil {
+			t.Fatalf("batch %d: unexpected err: %v", i, err)
+		}
+		bs, err := ReadRecordBatches(batch.AppendTo(nil))
+		if err != nil || len(bs) != 1 {
+			t.Fatalf("batch %d: got %d batches and err %v, expected 1 batch", i, len(bs), err)
+		}
+		if got := bs[0]; got.FirstSequence != expSeq || got.ProducerID != 10 || got.ProducerEpoch != 1 {
+			t.Errorf("batch %d: got sequence %d producer %d epoch %d != exp %d 10 1",
+				i, got.FirstSequence, got.ProducerID, got.ProducerEpoch, expSeq)
+		}
+		expSeq += int32(n)
+		b.FirstSequence = b.NextSequence()
+		if b.FirstSequence != expSeq {
+			t.Errorf("batch %d: got next sequence %d != exp %d", i, b.FirstSequence, expSeq)
+		}
+	}
+
+	b.FirstSequence = 1<<31 - 2
+	b.Reset()
+	b.Add(Record{})
+	b.Add(Record{})
+	b.Add(Record{})
+	if got := b.NextSequence(); got != 1 {
+		t.Errorf("got wrapped next sequence %d != exp 1", got)
+	}
+
+	if got := NewRecordBatchBuilder().NextSequence(); got != -1 {
+		t.Errorf("got non-idempotent next sequence %d != exp -1", got)
+	}
+}
+
+func TestRecordBatchBuilderEstimatedSize(t *testing.T) {
+	b := NewRecordBatchBuilder()
+	b.FirstTimestamp = 1000
+	if got, exp := b.EstimatedSize(), len(mustBuild(t, b, CodecNone)); got != exp {
+		t.Errorf("empty: got estimate %d != exp %d", got, exp)
+	}
+
+	for i := 0; i < 20; i++ {
+		b.Add(Record{
+			TimestampDelta64: int64(i * 3),
+			Key:              []byte("key"),
+			Value:            bytes.Repeat([]byte("value"), i),
+			Headers:          []Header{{Key: "h", Value: []byte("hv")}},
+		})
+	}
+	estimate := b.EstimatedSize()
+	if exp := len(mustBuild(t, b, CodecNone)); estimate != exp {
+		t.Errorf("got estimate %d != exp uncompressed size %d", estimate, exp)
+	}
+	if compressed := len(mustBuild(t, b, CodecGzip)); compressed > estimate {
+		t.Errorf("got compressed size %d > estimate %d", compressed, estimate)
+	}
+}
+
+func mustBuild(t *testing.T, b *RecordBatchBuilder, codec int8) []byte {
+	t.Helper()
+	batch, err := b.Build(codec)
+	if err != nil {
+		t.Fatalf("unable to build: %v", err)
+	}
+	return batch.AppendTo(nil)
+}
+
+func TestRecordBatchWriter(t *testing.T) {
+	type rec struct {
+		key, value string
+		ts         int64
+	}
+	recs := []rec{{"k0", "v0", 100}, {"k1", "v1", 105}, {"k2", "v2", 103}}
+	headers := []Header{{Key: "h", Value: []byte("hv")}}
+
+	for _, codec := range []int8{CodecNone, CodecGzip} {
+		w := NewRecordBatchWriter()
+		w.ProducerID, w.ProducerEpoch, w.FirstSequence = 4, 1, 10
+		b := NewRecordBatchBuilder()
+		b.ProducerID, b.ProducerEpoch, b.FirstSequence = 4, 1, 10
+		b.FirstTimestamp = recs[0].ts
+		for _, r := range recs {
+			w.AddRecord([]byte(r.key), []byte(r.value), headers, r.ts)
+			b.Add(Record{Key: []byte(r.key), Value: []byte(r.value), Headers: headers, TimestampDelta64: r.ts - recs[0].ts})
+		}
+
+		got, err := w.Finish(codec)
+		if err != nil {
+			t.Fatalf("codec %d: unexpected writer err: %v", codec, err)
+		}
+		batch, err := b.Build(codec)
+		if err != nil {
+			t.Fatalf("codec %d: unexpected builder err: %v", codec, err)
+		}
+		if exp := batch.AppendTo(nil); !bytes.Equal(got, exp) {
+			t.Errorf("codec %d: writer output %x != builder output %x", codec, got, exp)
+		}
+		bs, err := ReadRecordBatches(got)
+		if err != nil || len(bs) != 1 || bs[0].MaxTimestamp != 105 || bs[0].NumRecords != 3 {
+			t.Errorf("codec %d: got batches %v and err %v, expected one valid batch", codec, bs, err)
+		}
+
+		w.Reset()
+		if w.NumRecords() != 0 || w.BufferedBytes() != 0 {
+			t.Errorf("codec %d: writer not empty after Reset", codec)
+		}
+	}
+}
+
+func TestBuildBatchWithRecords(t *testing.T) {
+	recs := []Record{
+		{OffsetDelta: 0, Key: []byte("a"), Value: []byte("1")},
+		{OffsetDelta: 3, Key: []byte("b"), Value: []byte("2"), TimestampDelta64: 5},
+		{OffsetDelta: 7, Key: []byte("a")}, // tombstone
+	}
+	batch := BuildBatchWithRecords(100, recs)
+
+	bs, err := ReadRecordBatches(batch.AppendTo(nil))
+	if err != nil || len(bs) != 1 {
+		t.Fatalf("got %d batches and err %v, expected 1 batch", len(bs), err)
+	}
+	got := bs[0]
+	if got.FirstOffset != 100 || got.LastOffsetDelta != 7 || got.NumRecords != 3 || got.MaxTimestamp != 5 {
+		t.Errorf("got first offset %d, last delta %d, %d records, max timestamp %d != exp 100, 7, 3, 5",
+			got.FirstOffset, got.LastOffsetDelta, got.NumRecords, got.MaxTimestamp)
+	}
+	rs, err := ReadRecords(int(got.NumRecords), got.Records)
+	if err != nil {
+		t.Fatalf("unable to read records: %v", err)
+	}
+	for i, r := range rs {
+		if r.OffsetDelta != recs[i].OffsetDelta || !bytes.Equal(r.Key, recs[i].Key) || !bytes.Equal(r.Value, recs[i].Value) {
+			t.Errorf("record %d: got delta %d key %q value %q != exp %d %q %q",
+				i, r.OffsetDelta, r.Key, r.Value, recs[i].OffsetDelta, recs[i].Key, recs[i].Value)
+		}
+	}
+	if rs[2].Value != nil {
+		t.Errorf("got tombstone value %q, expected nil", rs[2].Value)
+	}
+	if recs[0].Length != 0 {
+		t.Error("input records were unexpectedly modified")
+	}
+}
@@ -0,0 +1,316 @@
+package kmsg
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kmsg/internal/kbin"
+)
+
+// testRecords returns n simple uncompressed records.
+func testRecords(n int) []Record {
+	rs := make([]Record, n)
+	for i := range rs {
+		r := &rs[i]
+		r.OffsetDelta = int32(i)
+		r.Key = []byte{'k', byte('0' + i)}
+		r.Value = []byte{'v', byte('0' + i)}
+		r.Headers = []Header{{Key: "h", Value: []byte{byte(i)}}}
+		r.Length = int32(len(r.AppendTo(nil)) - 1)
+	}
+	return rs
+}
+
+// testBatch returns a serialized, uncompressed batch containing rs.
+func testBatch(firstOffset int64, rs []Record) []byte {
+	return testBatchCodec(firstOffset, CodecNone, rs)
+}
+
+// testBatchCodec returns a serialized batch containing rs, compressed with
+// the given codec. Only gzip is actually compressed; other codecs only have
+// their attribute bits set.
+func testBatchCodec(firstOffset int64, codec int8, rs []Record) []byte {
+	b := NewRecordBatch()
+	b.FirstOffset = firstOffset
+	b.PartitionLeaderEpoch = -1
+	b.Magic = 2
+	b.LastOffsetDelta = int32(len(rs) - 1)
+	b.ProducerID = -1
+	b.ProducerEpoch = -1
+	b.FirstSequence = -1
+	b.NumRecords = int32(len(rs))
+	for i := range rs {
+		b.Records = rs[i].AppendTo(b.Records)
+	}
+	if codec == CodecGzip {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		w.Write(b.Records)
+		w.Close()
+		b.Records = buf.Bytes()
+	}
+	b.Attributes |= int16(codec)
+	b.Length = int32(49 + len(b.Records))
+	raw := b.AppendTo(nil)
+	kbin.AppendInt32(raw[17:17], int32(crc32.Checksum(raw[21:], crc32c)))
+	return raw
+}
+
+func TestReadRecordBatches(t *testing.T) {
+	var in []byte
+	in = append(in, testBatch(0, testRecords(2))...)
+	in = append(in, testBatch(2, testRecords(3))...)
+	full := len(in)
+	in = append(in, testBatch(5, testRecords(1))[:30]...) // partial trailing batch
+
+	bs, err := ReadRecordBatches(in)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(bs) != 2 || bs[0].NumRecords != 2 || bs[1].FirstOffset != 2 {
+		t.Fatalf("got unexpected batches %v", bs)
+	}
+	rs, err := ReadRecords(int(bs[1].NumRecords), bs[1].Records)
+	if err != nil {
+		t.Fatalf("unexpected records err: %v", err)
+	}
+	if exp := testRecords(3); !reflect.DeepEqual(rs, exp) {
+		t.Errorf("got records %v != exp %v", rs, exp)
+	}
+	if _, err := ReadRecords(4, bs[1].Records); err == nil {
+		t.Error("expected error reading more records than exist")
+	}
+
+	// Reusing dst should give the same results without reallocating.
+	into, err := ReadRecordBatchesInto(bs[:1], in)
+	if err != nil || !reflect.DeepEqual(into, bs) || &into[0] != &bs[0] {
+		t.Errorf("read into did not reuse or match: err %v, got %v, exp %v", err, into, bs)
+	}
+
+	in[full-1] ^= 0xff // corrupt the final complete batch
+	if bs, err := ReadRecordBatches(in); err != ErrEncodedCRCMismatch || len(bs) != 1 {
+		t.Errorf("got %d batches and err %v, expected 1 batch and crc mismatch", len(bs), err)
+	}
+}
+
+func BenchmarkReadRecordBatches(b *testing.B) {
+	var in []byte
+	for i := 0; i < 10; i++ {
+		in = append(in, testBatch(int64(i*10), testRecords(10))...)
+	}
+	b.Run("alloc", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			bs, _ := ReadRecordBatches(in)
+			for j := range bs {
+				ReadRecords(int(bs[j].NumRecords), bs[j].Records)
+			}
+		}
+	})
+	b.Run("into", func(b *testing.B) {
+		b.ReportAllocs()
+		var bs []RecordBatch
+		var rs []Record
+		for i := 0; i < b.N; i++ {
+			bs, _ = ReadRecordBatchesInto(bs, in)
+			for j := range bs {
+				rs, _ = ReadRecordsInto(rs, int(bs[j].NumRecords), bs[j].Records)
+			}
+		}
+	})
+}
+
+func TestReadRecordsMalformedLength(t *testing.T) {
+	in := append(testRecords(1)[0].AppendTo(nil), 0xff, 0xff, 0xff, 0xff, 0x7f)
+	rs, err := ReadRecords(2, in)
+	if err != kbin.ErrVarintOverflow || len(rs) != 1 {
+		t.Errorf("got %d records and err %v, expected 1 record and varint overflow", len(rs), err)
+	}
+}
+
+func TestReadRecordBatchesCorrupt(t *testing.T) {
+	corrupt := testBatch(2, testRecords(1))
+	kbin.AppendInt32(corrupt[8:8], 20) // too short to hold a batch header
+
+	var in []byte
+	in = append(in, testBatch(0, testRecords(2))...)
+	in = append(in, corrupt[:12+20]...)
+	in = append(in, testBatch(3, testRecords(2))...)
+
+	bs, err := ReadRecordBatches(in)
+	if err == nil || len(bs) != 1 {
+		t.Errorf("corrupt middle batch: got %d batches and err %v, expected 1 batch and an error", len(bs), err)
+	}
+
+	// A truncated final batch is still a clean stop.
+	in = append(testBatch(0, testRecords(2)), testBatch(2, testRecords(2))[:50]...)
+	bs, err = ReadRecordBatches(in)
+	if err != nil || len(bs) != 1 {
+		t.Errorf("truncated final batch: got %d batches and err %v, expected 1 batch and no error", len(bs), err)
+	}
+}
+
+func TestReadRecordBatchesSkipCRCCheck(t *testing.T) {
+	in := testBatch(0, testRecords(2))
+	in[17]++ // corrupt the CRC
+
+	if _, err := ReadRecordBatchesOpts(in); !errors.Is(err, ErrEncodedCRCMismatch) {
+		t.Errorf("got err %v without skipping, expected crc mismatch", err)
+	}
+	bs, err := ReadRecordBatchesOpts(in, SkipCRCCheck())
+	if err != nil || len(bs) != 1 || bs[0].NumRecords != 2 {
+		t.Errorf("got %d batches and err %v when skipping, expected 1 batch with 2 records", len(bs), err)
+	}
+}
+
+func TestRecordBatchAttributes(t *testing.T) {
+	b := NewRecordBatch()
+	b.Attributes = int16(CodecZstd) | RecordBatchAttrTransactional | RecordBatchAttrControl
+	if b.Codec() != CodecZstd || !b.IsTransactional() || !b.IsControl() || b.HasLogAppendTime() {
+		t.Errorf("got codec %d, transactional %v, control %v, log append time %v",
+			b.Codec(), b.IsTransactional(), b.IsControl(), b.HasLogAppendTime())
+	}
+}
+
+func TestRecordBatchDeleteHorizon(t *testing.T) {
+	b := NewRecordBatch()
+	b.Attributes = int16(CodecGzip)
+	if b.HasDeleteHorizon() {
+		t.Error("got delete horizon with the bit unset")
+	}
+
+	b.Attributes |= RecordBatchAttrDeleteHorizon
+	if !b.HasDeleteHorizon() || b.Codec() != CodecGzip {
+		t.Errorf("got delete horizon %v and codec %d, expected delete horizon and gzip", b.HasDeleteHorizon(), b.Codec())
+	}
+
+	b.Attributes &^= RecordBatchAttrDeleteHorizon
+	if b.HasDeleteHorizon() {
+		t.Error("got delete horizon after clearing the bit")
+	}
+}
+
+func TestRecordBatchCompressionRatio(t *testing.T) {
+	rs := testRecords(50)
+	for i := range rs {
+		rs[i].Value = bytes.Repeat([]byte("compressible"), 10)
+		rs[i].Length = int32(rs[i].bodySize())
+	}
+
+	for _, test := range []struct {
+		codec int8
+		check func(float64) bool
+	}{
+		{CodecNone, func(r float64) bool { return r == 1 }},
+		{CodecGzip, func(r float64) bool { return r > 0 && r < 1 }},
+	} {
+		bs, err := ReadRecordBatches(testBatchCodec(0, test.codec, rs))
+		if err != nil || len(bs) != 1 {
+			t.Fatalf("codec %d: got %d batches and err %v, expected 1 batch", test.codec, len(bs), err)
+		}
+		ratio, err := bs[0].CompressionRatio()
+		if err != nil || !test.check(ratio) {
+			t.Errorf("codec %d: got unexpected ratio %v and err %v", test.codec, ratio, err)
+		}
+	}
+}
+
+func TestRecordBatchProducerState(t *testing.T) {
+	b := NewRecordBatch()
+	b.ProducerID = 12
+	b.ProducerEpoch = 3
+	b.FirstSequence = 40
+	b.Attributes = RecordBatchAttrTransactional
+
+	id, epoch, seq, txn := b.ProducerState()
+	if id != 12 || epoch != 3 || seq != 40 || !txn {
+		t.Errorf("got (%d, %d, %d, %v) != exp (12, 3, 40, true)", id, epoch, seq, txn)
+	}
+
+	b.Attributes = 0
+	if _, _, _, txn := b.ProducerState(); txn {
+		t.Error("got transactional for a non-transactional batch")
+	}
+}
+
+func TestRecordBatchNextOffset(t *testing.T) {
+	var b RecordBatch
+	if err := b.ReadFrom(testBatch(10, testRecords(3))); err != nil {
+		t.Fatalf("unable to read batch: %v", err)
+	}
+	if got := b.NextOffset(); got != 13 {
+		t.Errorf("got %d != exp 13", got)
+	}
+
+	// A compacted batch retains its LastOffsetDelta.
+	rs := testRecords(1)
+	b = *BuildBatchWithRecords(20, rs)
+	b.LastOffsetDelta = 4
+	if got := b.NextOffset(); got != 25 {
+		t.Errorf("compacted: got %d != exp 25", got)
+	}
+}
+
+func TestReadRecordsShort(t *testing.T) {
+	full := testRecords(1)[0].AppendTo(nil)
+	_, err := ReadRecords(1, full[:len(full)-3])
+	if !errors.Is(err, kbin.ErrNotEnoughData) {
+		t.Fatalf("got err %v, expected not enough data", err)
+	}
+	var short *kbin.ErrShort
+	if !errors.As(err, &short) || short.Need != len(full) || short.Have != len(full)-3 {
+		t.Errorf("got short %v, expected need %d have %d", short, len(full), len(full)-3)
+	}
+}
+
+func TestReadRecordsPartial(t *testing.T) {
+	rs := testRecords(3)
+	var in []byte
+	for i := range rs {
+		in = rs[i].AppendTo(in)
+	}
+	full := len(in)
+	in = in[:full-4] // truncate the final record
+
+	got, leftover, err := ReadRecordsPartial(in)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("got %d records and err %v, expected 2 records", len(got), err)
+	}
+	if !reflect.DeepEqual(got, rs[:2]) {
+		t.Errorf("got records %v != exp %v", got, rs[:2])
+	}
+	if exp := rs[2].Size() - 4; len(leftover) != exp {
+		t.Errorf("got %d leftover bytes != exp %d", len(leftover), exp)
+	}
+
+	got, leftover, err = ReadRecordsPartial(in[:full-rs[2].Size()])
+	if err != nil || len(got) != 2 || len(leftover) != 0 {
+		t.Errorf("got %d records, %d leftover bytes, and err %v, expected 2 records and nothing leftover", len(got), len(leftover), err)
+	}
+
+	if _, _, err := ReadRecordsPartial(kbin.AppendVarint(nil, -2)); err == nil {
+		t.Error("expected error for a negative record length")
+	}
+}
+
+func TestReadBatchLeaderEpoch(t *testing.T) {
+	b := NewRecordBatch()
+	b.Magic = 2
+	b.PartitionLeaderEpoch = 37
+	raw := b.AppendTo(nil)
+
+	if epoch, err := ReadBatchLeaderEpoch(raw); err != nil || epoch != 37 {
+		t.Errorf("got epoch %d and err %v, expected 37", epoch, err)
+	}
+	if _, err := ReadBatchLeaderEpoch(raw[:16]); !errors.Is(err, kbin.ErrNotEnoughData) {
+		t.Errorf("got err %v for short input, expected not enough data", err)
+	}
+	v1 := MessageV1{Magic: 1}
+	if _, err := ReadBatchLeaderEpoch(v1.AppendTo(nil)); err == nil {
+		t.Error("expected error for a v1 message")
+	}
+}
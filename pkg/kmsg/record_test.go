@@ -0,0 +1,35 @@
+package kmsg
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRecordSize(t *testing.T) {
+	big := bytes.Repeat([]byte("x"), 300)
+	for i, r := range []Record{
+		{},
+		{Key: []byte("key"), Value: []byte("value")},
+		{Value: big, TimestampDelta64: 1 << 40, OffsetDelta: 1000},
+		{TimestampDelta: -5, Key: []byte{}, Value: nil},
+		{Key: []byte("k"), Headers: []Header{{Key: "h1", Value: []byte("v1")}, {Key: "", Value: nil}, {Key: "h3", Value: big}}},
+	} {
+		r.Length = int32(r.bodySize())
+		if got, exp := r.Size(), len(r.AppendTo(nil)); got != exp {
+			t.Errorf("#%d: got size %d != exp %d", i, got, exp)
+		}
+	}
+}
+
+func TestRecordAttributesRoundTrip(t *testing.T) {
+	r := Record{Attributes: 0x5a, Key: []byte("k"), Value: []byte("v")}
+	r.Length = int32(r.bodySize())
+
+	var got Record
+	if err := got.ReadFrom(r.AppendTo(nil)); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.Attributes != 0x5a {
+		t.Errorf("got attributes %x != exp %x", got.Attributes, 0x5a)
+	}
+}
@@ -0,0 +1,69 @@
+package kmsg
+
+import "reflect"
+
+// RewriteTopics replaces, in place, every topic name in r with the result of
+// fn. This can be used in a proxy that namespaces topics, such as by adding a
+// tenant prefix to every topic a client requests.
+//
+// Topic names are every field named Topic (a string or non-null *string) and
+// every string in a []string field named Topics or TopicNames, at any depth
+// in r. Topics identified only by topic ID, and topic names in generic
+// fields (such as the ResourceName of a config or ACL resource), are not
+// rewritten. Topic names within serialized bytes fields, such as the
+// assignments within a SyncGroupRequest, are also not rewritten.
+func RewriteTopics(r Request, fn func(string) string) {
+	rewriteTopics(reflect.ValueOf(r), fn)
+}
+
+// RewriteResponseTopics is the response counterpart of RewriteTopics,
+// replacing in place every topic name in r with the result of fn. In a proxy
+// that namespaces topics, this can be used to strip the namespace from every
+// topic in a response before returning the response to the client.
+//
+// The same fields are rewritten as in RewriteTopics. Topics within
+// serialized bytes fields, such as the member assignments in a
+// SyncGroupResponse, are not rewritten.
+func RewriteResponseTopics(r Response, fn func(string) string) {
+	rewriteTopics(reflect.ValueOf(r), fn)
+}
+
+func rewriteTopics(v reflect.Value, fn func(string) string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			rewriteTopics(v.Elem(), fn)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			rewriteTopics(v.Index(i), fn)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := v.Field(i)
+			if !f.CanSet() {
+				continue
+			}
+			switch name := t.Field(i).Name; {
+			case name == "Topic" && f.Kind() == reflect.String:
+				f.SetString(fn(f.String()))
+			case name == "Topic" && f.Kind() == reflect.Ptr && f.Type().Elem().Kind() == reflect.String:
+				if !f.IsNil() {
+					rewritten := fn(f.Elem().String())
+					f.Set(reflect.ValueOf(&rewritten))
+				}
+			case (name == "Topics" || name == "TopicNames") && f.Type() == reflect.TypeOf([]string(nil)):
+				for j := 0; j < f.Len(); j++ {
+					s := f.Index(j)
+					s.SetString(fn(s.String()))
+				}
+			default:
+				rewriteTopics(f, fn)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package kmsg
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRewriteTopics(t *testing.T) {
+	prefix := func(topic string) string { return "tenant." + topic }
+
+	produce := NewPtrProduceRequest()
+	for _, topic := range []string{"foo", "bar"} {
+		rt := NewProduceRequestTopic()
+		rt.Topic = topic
+		rt.Partitions = append(rt.Partitions, NewProduceRequestTopicPartition())
+		produce.Topics = append(produce.Topics, rt)
+	}
+	RewriteTopics(produce, prefix)
+	if produce.Topics[0].Topic != "tenant.foo" || produce.Topics[1].Topic != "tenant.bar" {
+		t.Errorf("produce: got unexpected topics %v", produce.Topics)
+	}
+
+	fetch := NewPtrFetchRequest()
+	ft := NewFetchRequestTopic()
+	ft.Topic = "foo"
+	fetch.Topics = append(fetch.Topics, ft)
+	forgotten := NewFetchRequestForgottenTopic()
+	forgotten.Topic = "bar"
+	fetch.ForgottenTopics = append(fetch.ForgottenTopics, forgotten)
+	RewriteTopics(fetch, prefix)
+	if fetch.Topics[0].Topic != "tenant.foo" || fetch.ForgottenTopics[0].Topic != "tenant.bar" {
+		t.Errorf("fetch: got unexpected topics %v, forgotten %v", fetch.Topics, fetch.ForgottenTopics)
+	}
+
+	shared := "foo"
+	metadata := MetadataNoTopics()
+	metadata.Topics = append(metadata.Topics, MetadataRequestTopic{Topic: &shared}, MetadataRequestTopic{})
+	RewriteTopics(metadata, prefix)
+	if *metadata.Topics[0].Topic != "tenant.foo" || metadata.Topics[1].Topic != nil || shared != "foo" {
+		t.Errorf("metadata: got unexpected topics %v (shared %q)", metadata.Topics, shared)
+	}
+
+	del := NewPtrDeleteTopicsRequest()
+	del.TopicNames = []string{"foo", "bar"}
+	RewriteTopics(del, prefix)
+	if exp := []string{"tenant.foo", "tenant.bar"}; !reflect.DeepEqual(del.TopicNames, exp) {
+		t.Errorf("delete: got topic names %v != exp %v", del.TopicNames, exp)
+	}
+}
+
+func TestRewriteResponseTopics(t *testing.T) {
+	strip := func(topic string) string { return strings.TrimPrefix(topic, "tenant.") }
+
+	metadata := testMetadata(map[string][]int32{"tenant.foo": {1}, "tenant.bar": {2}})
+	RewriteResponseTopics(metadata, strip)
+	var got []string
+	for _, t := range metadata.Topics {
+		got = append(got, *t.Topic)
+	}
+	sort.Strings(got)
+	if exp := []string{"bar", "foo"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("metadata: got topics %v != exp %v", got, exp)
+	}
+
+	fetch := testFetchResponse(testBatch(0, testRecords(1)))
+	fetch.Topics[0].Topic = "tenant.foo"
+	records := fetch.Topics[0].Partitions[0].RecordBatches
+	RewriteResponseTopics(fetch, strip)
+	if fetch.Topics[0].Topic != "foo" {
+		t.Errorf("fetch: got topic %q != exp foo", fetch.Topics[0].Topic)
+	}
+	if !reflect.DeepEqual(fetch.Topics[0].Partitions[0].RecordBatches, records) {
+		t.Error("fetch: record batches were unexpectedly modified")
+	}
+}
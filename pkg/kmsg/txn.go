@@ -0,0 +1,171 @@
+package kmsg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TxnMarker is a transaction marker to write with BuildWriteTxnMarkers.
+type TxnMarker struct {
+	// ProducerID and ProducerEpoch are the producer ID and epoch of the
+	// transaction being ended.
+	ProducerID    int64
+	ProducerEpoch int16
+
+	// Committed is true if the transaction is being committed, or false if
+	// it is being aborted.
+	Committed bool
+
+	// CoordinatorEpoch is the epoch of the transaction coordinator writing
+	// the marker.
+	CoordinatorEpoch int32
+
+	// Partitions are the topics and partitions to write the marker to.
+	Partitions map[string][]int32
+}
+
+// BuildWriteTxnMarkers returns a WriteTxnMarkersRequest writing the given
+// markers. The topics within each marker are sorted so that the request is
+// deterministic; markers and partitions are kept in the order given.
+func BuildWriteTxnMarkers(markers []TxnMarker) *WriteTxnMarkersRequest {
+	req := NewPtrWriteTxnMarkersRequest()
+	for _, m := range markers {
+		rm := NewWriteTxnMarkersRequestMarker()
+		rm.ProducerID = m.ProducerID
+		rm.ProducerEpoch = m.ProducerEpoch
+		rm.Committed = m.Committed
+		rm.CoordinatorEpoch = m.CoordinatorEpoch
+		for topic, partitions := range m.Partitions {
+			rt := NewWriteTxnMarkersRequestMarkerTopic()
+			rt.Topic = topic
+			rt.Partitions = append(rt.Partitions, partitions...)
+			rm.Topics = append(rm.Topics, rt)
+		}
+		sort.Slice(rm.Topics, func(i, j int) bool { return rm.Topics[i].Topic < rm.Topics[j].Topic })
+		req.Markers = append(req.Markers, rm)
+	}
+	return req
+}
+
+// ErrorCodes returns a map of producer IDs to topics to partitions to the
+// error code for writing the marker for that producer ID to that partition.
+// Each error code can be converted to an error with kerr.ErrorForCode; a zero
+// code means the marker was written successfully.
+func (v *WriteTxnMarkersResponse) ErrorCodes() map[int64]map[string]map[int32]int16 {
+	codes := make(map[int64]map[string]map[int32]int16, len(v.Markers))
+	for i := range v.Markers {
+		m := &v.Markers[i]
+		topics := codes[m.ProducerID]
+		if topics == nil {
+			topics = make(map[string]map[int32]int16, len(m.Topics))
+			codes[m.ProducerID] = topics
+		}
+		for j := range m.Topics {
+			t := &m.Topics[j]
+			partitions := topics[t.Topic]
+			if partitions == nil {
+				partitions = make(map[int32]int16, len(t.Partitions))
+				topics[t.Topic] = partitions
+			}
+			for k := range t.Partitions {
+				p := &t.Partitions[k]
+				partitions[p.Partition] = p.ErrorCode
+			}
+		}
+	}
+	return codes
+}
+
+// BuildInitProducerID returns an InitProducerIDRequest to initialize a new
+// producer ID. A nil transactionalID initializes a producer ID for an
+// idempotent producer, in which case the broker ignores txnTimeoutMs. A
+// non-nil transactionalID initializes (or fences and bumps the epoch of) the
+// producer ID for that transactional ID, with txnTimeoutMs as the maximum
+// time a transaction can be open before the broker aborts it.
+//
+// To recover an existing producer ID, use BuildReinitProducerID.
+func BuildInitProducerID(transactionalID *string, txnTimeoutMs int32) *InitProducerIDRequest {
+	req := NewPtrInitProducerIDRequest()
+	req.TransactionalID = transactionalID
+	req.TransactionTimeoutMillis = txnTimeoutMs
+	return req
+}
+
+// BuildReinitProducerID is the same as BuildInitProducerID, but includes the
+// producer's current ID and epoch (KIP-360). This allows the broker to bump
+// the epoch of the existing producer ID rather than assigning a new one,
+// which is how a producer recovers from UNKNOWN_PRODUCER_ID or
+// INVALID_PRODUCER_EPOCH without losing its idempotence guarantees.
+//
+// The current producer ID and epoch are only serialized in v3+; prior
+// versions always initialize a new producer ID.
+func BuildReinitProducerID(transactionalID *string, txnTimeoutMs int32, producerID int64, producerEpoch int16) *InitProducerIDRequest {
+	req := BuildInitProducerID(transactionalID, txnTimeoutMs)
+	req.ProducerID = producerID
+	req.ProducerEpoch = producerEpoch
+	return req
+}
+
+// ProducerState is the state of an active producer on a partition, as
+// returned in a DescribeProducersResponse (KIP-664).
+type ProducerState struct {
+	ProducerID    int64
+	ProducerEpoch int32
+
+	// LastSequence is the last sequence number the producer wrote to the
+	// partition, or -1 if unknown.
+	LastSequence int32
+
+	// LastTimestamp is the timestamp of the last record the producer wrote
+	// to the partition, or -1 if unknown.
+	LastTimestamp int64
+
+	// CoordinatorEpoch is the epoch of the transaction coordinator that
+	// last wrote a marker for the producer, or -1 if none has.
+	CoordinatorEpoch int32
+
+	// CurrentTxnStartOffset is the first offset of the producer's ongoing
+	// transaction on the partition, or -1 if there is no ongoing
+	// transaction. A transaction that has been open for a long time is
+	// likely a hanging transaction, which blocks read committed consumers
+	// from progressing past this offset.
+	CurrentTxnStartOffset int64
+}
+
+// Producers returns the active producers on the given partition. This
+// returns an error if the partition is not in the response or if the
+// partition has an error code, which can be converted to an error with
+// kerr.ErrorForCode.
+func (v *DescribeProducersResponse) Producers(topic string, partition int32) ([]ProducerState, error) {
+	for i := range v.Topics {
+		t := &v.Topics[i]
+		if t.Topic != topic {
+			continue
+		}
+		for j := range t.Partitions {
+			p := &t.Partitions[j]
+			if p.Partition != partition {
+				continue
+			}
+			if p.ErrorCode != 0 {
+				if p.ErrorMessage != nil {
+					return nil, fmt.Errorf("topic %s partition %d: error code %d: %s", topic, partition, p.ErrorCode, *p.ErrorMessage)
+				}
+				return nil, fmt.Errorf("topic %s partition %d: error code %d", topic, partition, p.ErrorCode)
+			}
+			states := make([]ProducerState, 0, len(p.ActiveProducers))
+			for _, ap := range p.ActiveProducers {
+				states = append(states, ProducerState{
+					ProducerID:            ap.ProducerID,
+					ProducerEpoch:         ap.ProducerEpoch,
+					LastSequence:          ap.LastSequence,
+					LastTimestamp:         ap.LastTimestamp,
+					CoordinatorEpoch:      ap.CoordinatorEpoch,
+					CurrentTxnStartOffset: ap.CurrentTxnStartOffset,
+				})
+			}
+			return states, nil
+		}
+	}
+	return nil, fmt.Errorf("topic %s partition %d: not in the response", topic, partition)
+}
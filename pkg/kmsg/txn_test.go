@@ -0,0 +1,142 @@
+package kmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWriteTxnMarkers(t *testing.T) {
+	req := BuildWriteTxnMarkers([]TxnMarker{{
+		ProducerID:       7,
+		ProducerEpoch:    2,
+		Committed:        true,
+		CoordinatorEpoch: 3,
+		Partitions: map[string][]int32{
+			"foo": {0, 1, 2},
+			"bar": {5},
+		},
+	}})
+
+	for _, version := range []int16{0, 1} {
+		req.Version = version
+		got := WriteTxnMarkersRequest{Version: version}
+		if err := got.ReadFrom(req.AppendTo(nil)); err != nil {
+			t.Fatalf("v%d: unable to read request: %v", version, err)
+		}
+		if !Equal(&got, req) {
+			t.Errorf("v%d: got %v != exp %v", version, got, req)
+		}
+		if len(got.Markers) != 1 {
+			t.Fatalf("v%d: got %d markers != exp 1", version, len(got.Markers))
+		}
+		m := got.Markers[0]
+		if m.ProducerID != 7 || m.ProducerEpoch != 2 || !m.Committed || m.CoordinatorEpoch != 3 {
+			t.Errorf("v%d: got unexpected marker %v", version, m)
+		}
+		if len(m.Topics) != 2 || m.Topics[0].Topic != "bar" || !reflect.DeepEqual(m.Topics[1].Partitions, []int32{0, 1, 2}) {
+			t.Errorf("v%d: got unexpected topics %v", version, m.Topics)
+		}
+	}
+
+	resp := req.ResponseKind().(*WriteTxnMarkersResponse)
+	for _, m := range req.Markers {
+		rm := NewWriteTxnMarkersResponseMarker()
+		rm.ProducerID = m.ProducerID
+		for _, t := range m.Topics {
+			rt := NewWriteTxnMarkersResponseMarkerTopic()
+			rt.Topic = t.Topic
+			for _, p := range t.Partitions {
+				rp := NewWriteTxnMarkersResponseMarkerTopicPartition()
+				rp.Partition = p
+				if p == 1 {
+					rp.ErrorCode = 6
+				}
+				rt.Partitions = append(rt.Partitions, rp)
+			}
+			rm.Topics = append(rm.Topics, rt)
+		}
+		resp.Markers = append(resp.Markers, rm)
+	}
+	exp := map[int64]map[string]map[int32]int16{
+		7: {
+			"foo": {0: 0, 1: 6, 2: 0},
+			"bar": {5: 0},
+		},
+	}
+	if got := resp.ErrorCodes(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("got error codes %v != exp %v", got, exp)
+	}
+}
+
+func TestBuildInitProducerID(t *testing.T) {
+	idempotent := BuildInitProducerID(nil, 60000)
+	if idempotent.TransactionalID != nil || idempotent.ProducerID != -1 || idempotent.ProducerEpoch != -1 {
+		t.Errorf("idempotent: got unexpected %v", idempotent)
+	}
+
+	txnID := "txn"
+	txn := BuildInitProducerID(&txnID, 60000)
+	if txn.TransactionalID == nil || *txn.TransactionalID != "txn" || txn.TransactionTimeoutMillis != 60000 || txn.ProducerID != -1 {
+		t.Errorf("transactional: got unexpected %v", txn)
+	}
+
+	reinit := BuildReinitProducerID(&txnID, 60000, 123, 4)
+	reinit.SetVersion(3)
+	var got InitProducerIDRequest
+	got.SetVersion(3)
+	if err := got.ReadFrom(reinit.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to read reinit: %v", err)
+	}
+	if got.TransactionalID == nil || *got.TransactionalID != "txn" || got.ProducerID != 123 || got.ProducerEpoch != 4 {
+		t.Errorf("reinit: got unexpected %v", got)
+	}
+}
+
+func TestDescribeProducersResponseProducers(t *testing.T) {
+	resp := NewPtrDescribeProducersResponse()
+	rt := NewDescribeProducersResponseTopic()
+	rt.Topic = "foo"
+	rp := NewDescribeProducersResponseTopicPartition()
+	rp.Partition = 1
+	for _, id := range []int64{7, 8} {
+		ap := NewDescribeProducersResponseTopicPartitionActiveProducer()
+		ap.ProducerID = id
+		ap.ProducerEpoch = 2
+		ap.LastSequence = 10
+		ap.LastTimestamp = 1000
+		ap.CoordinatorEpoch = 3
+		if id == 8 {
+			ap.CurrentTxnStartOffset = 50
+		}
+		rp.ActiveProducers = append(rp.ActiveProducers, ap)
+	}
+	failed := NewDescribeProducersResponseTopicPartition()
+	failed.Partition = 2
+	failed.ErrorCode = 6
+	rt.Partitions = append(rt.Partitions, rp, failed)
+	resp.Topics = append(resp.Topics, rt)
+
+	var decoded DescribeProducersResponse
+	if err := decoded.ReadFrom(resp.AppendTo(nil)); err != nil {
+		t.Fatalf("unable to decode: %v", err)
+	}
+
+	got, err := decoded.Producers("foo", 1)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	exp := []ProducerState{
+		{ProducerID: 7, ProducerEpoch: 2, LastSequence: 10, LastTimestamp: 1000, CoordinatorEpoch: 3, CurrentTxnStartOffset: -1},
+		{ProducerID: 8, ProducerEpoch: 2, LastSequence: 10, LastTimestamp: 1000, CoordinatorEpoch: 3, CurrentTxnStartOffset: 50},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+
+	if _, err := decoded.Producers("foo", 2); err == nil {
+		t.Error("expected an error for a failed partition")
+	}
+	if _, err := decoded.Producers("bar", 0); err == nil {
+		t.Error("expected an error for a missing partition")
+	}
+}
@@ -0,0 +1,94 @@
+package kmsg
+
+import "reflect"
+
+// StripToVersion sets the version of r to v and resets any field that does
+// not exist at version v to its default, leaving r valid to issue at v. This
+// is a best effort downgrade: values in fields that were introduced after (or
+// removed before) v are silently dropped.
+//
+// Which fields exist at which versions is driven by the generated
+// serialization of r: r is encoded at v and decoded back into r. Thus,
+// after stripping, any bytes fields in r no longer alias what they aliased
+// before.
+//
+// If r is not a pointer to a struct, or if r cannot be decoded from its own
+// encoding, this only sets the version.
+func StripToVersion(r Request, v int16) {
+	r.SetVersion(v)
+	rv := reflect.ValueOf(r)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return
+	}
+	fresh, ok := reflect.New(rv.Elem().Type()).Interface().(Request)
+	if !ok {
+		return
+	}
+	fresh.SetVersion(v)
+	if err := fresh.ReadFrom(r.AppendTo(nil)); err != nil {
+		return
+	}
+	rv.Elem().Set(reflect.ValueOf(fresh).Elem())
+}
+
+// FlexibleAt returns the first version at which the request (and response)
+// for the given key uses flexible encoding, or false if the key is unknown or
+// no version of the request is flexible.
+func FlexibleAt(key int16) (int16, bool) {
+	r := RequestForKey(key)
+	if r == nil {
+		return -1, false
+	}
+	for v := int16(0); v <= r.MaxVersion(); v++ {
+		r.SetVersion(v)
+		if r.IsFlexible() {
+			return v, true
+		}
+	}
+	return -1, false
+}
+
+// FallbackVersion returns the version to retry req at after a broker rejected
+// req at the current version with UNSUPPORTED_VERSION, which is current-1. This
+// returns false if there is no lower version to fall back to. This package
+// supports every version of every request down to v0, so the minimum version
+// to fall back to is 0.
+//
+// The standard retry loop is to issue a request at the highest version both
+// the client and broker support, and on UNSUPPORTED_VERSION, fall back one
+// version at a time until the request succeeds or this returns false.
+func FallbackVersion(req Request, current int16) (int16, bool) {
+	if current > req.MaxVersion() {
+		current = req.MaxVersion() + 1
+	}
+	if current <= 0 {
+		return -1, false
+	}
+	return current - 1, true
+}
+
+// EachRequest calls fn with a new default request for every key in this
+// package, in key order. Each call receives a distinct request, which fn may
+// modify. Combined with VersionsOf, this can be used to test encoding or
+// decoding every request at every version.
+func EachRequest(fn func(r Request)) {
+	for key := int16(0); key <= MaxKey; key++ {
+		if r := RequestForKey(key); r != nil {
+			fn(r)
+		}
+	}
+}
+
+// VersionsOf returns every version of the request for the given key that this
+// package supports, in ascending order, or nil if the key is unknown.
+func VersionsOf(key int16) []int16 {
+	r := RequestForKey(key)
+	if r == nil {
+		return nil
+	}
+	versions := make([]int16, 0, r.MaxVersion()+1)
+	for v := int16(0); v <= r.MaxVersion(); v++ {
+		versions = append(versions, v)
+	}
+	return versions
+}
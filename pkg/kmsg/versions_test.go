@@ -0,0 +1,83 @@
+package kmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripToVersion(t *testing.T) {
+	{
+		req := NewPtrJoinGroupRequest()
+		req.SetVersion(JoinGroup.Request().MaxVersion())
+		req.Group = "g"
+		req.InstanceID = StringPtr("instance") // v5+
+		req.Reason = StringPtr("reason")       // v8+
+
+		StripToVersion(req, 5)
+		if req.GetVersion() != 5 || req.Group != "g" {
+			t.Errorf("got version %d group %q, exp 5 and g", req.GetVersion(), req.Group)
+		}
+		if req.InstanceID == nil || *req.InstanceID != "instance" {
+			t.Errorf("got instance %v, expected it to be kept", req.InstanceID)
+		}
+		if req.Reason != nil {
+			t.Errorf("got reason %v, expected it to be stripped", *req.Reason)
+		}
+
+		StripToVersion(req, 4)
+		if req.InstanceID != nil {
+			t.Errorf("got instance %v, expected it to be stripped", *req.InstanceID)
+		}
+	}
+
+	{
+		// CoordinatorKey is v0-v3, CoordinatorKeys is v4+.
+		req, _ := BuildFindCoordinator(CoordinatorKeyGroup, "foo", "bar")
+		StripToVersion(req, 3)
+		if req.CoordinatorKey != "foo" || req.CoordinatorKeys != nil {
+			t.Errorf("v3: got key %q keys %v, expected only the single key", req.CoordinatorKey, req.CoordinatorKeys)
+		}
+		req, _ = BuildFindCoordinator(CoordinatorKeyGroup, "foo", "bar")
+		StripToVersion(req, 4)
+		if req.CoordinatorKey != "" || len(req.CoordinatorKeys) != 2 {
+			t.Errorf("v4: got key %q keys %v, expected only the keys", req.CoordinatorKey, req.CoordinatorKeys)
+		}
+	}
+}
+
+func TestFallbackVersion(t *testing.T) {
+	req := NewPtrFetchRequest()
+	var seen []int16
+	for v, ok := int16(3), true; ok; v, ok = FallbackVersion(req, v) {
+		seen = append(seen, v)
+	}
+	if exp := []int16{3, 2, 1, 0}; !reflect.DeepEqual(seen, exp) {
+		t.Errorf("got versions %v != exp %v", seen, exp)
+	}
+	if v, ok := FallbackVersion(req, 100); !ok || v != req.MaxVersion() {
+		t.Errorf("got %d %v, expected fallback from above max to max %d", v, ok, req.MaxVersion())
+	}
+}
+
+func TestEachRequest(t *testing.T) {
+	seen := make(map[int16]bool)
+	EachRequest(func(r Request) {
+		if seen[r.Key()] {
+			t.Errorf("key %d visited twice", r.Key())
+		}
+		seen[r.Key()] = true
+
+		versions := VersionsOf(r.Key())
+		if len(versions) != int(r.MaxVersion())+1 || versions[0] != 0 || versions[len(versions)-1] != r.MaxVersion() {
+			t.Errorf("%s: got versions %v, expected 0 through %d", NameForKey(r.Key()), versions, r.MaxVersion())
+		}
+	})
+	for key := int16(0); key <= MaxKey; key++ {
+		if NameForKey(key) != "Unknown" && !seen[key] {
+			t.Errorf("key %d (%s) not visited", key, NameForKey(key))
+		}
+	}
+	if VersionsOf(-1) != nil {
+		t.Error("got versions for an unknown key")
+	}
+}